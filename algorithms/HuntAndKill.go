@@ -5,19 +5,19 @@ import (
 	"math/rand"
 )
 
-func HuntAndKill(g grid.Grid) {
-	size := g.Rows() * g.Cols()
+func HuntAndKill(g grid.Maze) {
+	size := g.Size()
 	node := g.CellForIndex(rand.Intn(size))
 	visited := make([]bool, size)
 	visited[node.Index()] = true
 	visits := 1
 
-	directions := make([]grid.Direction, (grid.WEST+1)*2)
-	for d := grid.NORTH; d <= grid.WEST; d++ {
-		directions[d] = d
-	}
-	dirOptions := directions[grid.WEST+1:]
-	directions = directions[:grid.WEST+1]
+	dirs := g.Directions()
+	directions := make([]grid.Direction, len(dirs)*2)
+	copy(directions, dirs)
+	copy(directions[len(dirs):], dirs)
+	dirOptions := directions[len(dirs):]
+	directions = directions[:len(dirs)]
 
 	for visits < size {
 		if len(dirOptions) > 0 { // kill - keep exploring around
@@ -26,7 +26,7 @@ func HuntAndKill(g grid.Grid) {
 			if next := node.Neighbor(dir); next != nil && !visited[next.Index()] {
 				visited[next.Index()] = true
 				visits++
-				g.Connect(node.Row(), node.Col(), dir)
+				g.ConnectCell(node, dir)
 
 				node = *next
 				dirOptions = dirOptions[:cap(dirOptions)]
@@ -36,31 +36,28 @@ func HuntAndKill(g grid.Grid) {
 				dirOptions = dirOptions[:len(dirOptions)-1]
 			}
 		} else { // hunt - find a new trailhead
-			hunt:
-			for r := 0; r < g.Rows(); r++ {
-				for c := 0; c < g.Cols(); c++ {
-					node = g.Cell(r, c)
-					if visited[node.Index()] {
-						continue
-					}
+			for idx := 0; idx < size; idx++ {
+				node = g.CellForIndex(idx)
+				if visited[node.Index()] {
+					continue
+				}
 
-					for d := grid.NORTH; d <= grid.WEST; d++ {
-						if n := node.Neighbor(d); n != nil && visited[n.Index()] {
-							dirOptions = append(dirOptions, d)
-						}
+				for _, d := range directions {
+					if n := node.Neighbor(d); n != nil && visited[n.Index()] {
+						dirOptions = append(dirOptions, d)
 					}
-					if len(dirOptions) != 0 {
-						i := grid.Direction(rand.Intn(len(dirOptions)))
-						dir := dirOptions[i]
-						g.Connect(r, c, dir)
-						visited[node.Index()] = true
-						visits++
+				}
+				if len(dirOptions) != 0 {
+					i := grid.Direction(rand.Intn(len(dirOptions)))
+					dir := dirOptions[i]
+					g.ConnectCell(node, dir)
+					visited[node.Index()] = true
+					visits++
 
-						dirOptions = dirOptions[:cap(dirOptions)]
-						copy(dirOptions, directions)
+					dirOptions = dirOptions[:cap(dirOptions)]
+					copy(dirOptions, directions)
 
-						break hunt
-					}
+					break
 				}
 			}
 		}
@@ -5,22 +5,23 @@ import (
 	"math/rand"
 )
 
-func AldousBroder(g grid.Grid) {
-	size := g.Rows() * g.Cols()
+func AldousBroder(g grid.Maze) {
+	size := g.Size()
 	node := g.CellForIndex(rand.Intn(size))
 	visited := make([]bool, size)
+	dirs := g.Directions()
 	visited[node.Index()] = true
 	visits := 1
 
 	for visits < size {
-		dir := grid.Direction(rand.Intn(int(grid.WEST) + 1))
-		if node.HasNeighbor(dir) {
-			if !visited[node.Neighbor(dir).Index()] {
-				visited[node.Neighbor(dir).Index()] = true
+		dir := dirs[rand.Intn(len(dirs))]
+		if next := node.Neighbor(dir); next != nil {
+			if !visited[next.Index()] {
+				visited[next.Index()] = true
 				visits++
-				g.Connect(node.Row(), node.Col(), dir)
+				g.ConnectCell(node, dir)
 			}
-			node = *node.Neighbor(dir)
+			node = *next
 		}
 	}
 }
@@ -6,24 +6,25 @@ import (
 )
 
 // AldousBroderWilsons runs AldousBroder until either the grid is half visited or it has run for size*4 iterations. Then it runs Wilson's algorithm until the grid is fully visited
-func AldousBroderWilsons(g grid.Grid) {
+func AldousBroderWilsons(g grid.Maze) {
 	// Aldous-broder
-	size := g.Rows() * g.Cols()
+	size := g.Size()
 	node := g.CellForIndex(rand.Intn(size))
 	visited := make([]bool, size)
+	dirs := g.Directions()
 	visits := 1
 
 	visited[node.Index()] = true
 	var i = 0
 	for visits < size/2 && i < size*4 {
-		dir := grid.Direction(rand.Intn(int(grid.WEST) + 1))
-		if node.HasNeighbor(dir) {
-			if !visited[node.Neighbor(dir).Index()] {
-				visited[node.Neighbor(dir).Index()] = true
+		dir := dirs[rand.Intn(len(dirs))]
+		if next := node.Neighbor(dir); next != nil {
+			if !visited[next.Index()] {
+				visited[next.Index()] = true
 				visits++
-				g.Connect(node.Row(), node.Col(), dir)
+				g.ConnectCell(node, dir)
 			}
-			node = *node.Neighbor(dir)
+			node = *next
 		}
 		i++
 	}
@@ -45,13 +46,21 @@ func AldousBroderWilsons(g grid.Grid) {
 	path := make([]grid.Cell, 0, size/2) // on large grids, size/2 is a reasonable initial memory guess
 	pathed[node.Index()] = true
 	for {
-		dir := grid.Direction(rand.Intn(int(grid.WEST) + 1))
+		dir := dirs[rand.Intn(len(dirs))]
 		if node.HasNeighbor(dir) {
 			next := *node.Neighbor(dir)
 
 			if visited[next.Index()] { // connect the path to the visited maze
 				for i := len(path) - 1; i >= 0; i-- {
-					g.Connect(next.Row(), next.Col(), g.CellDir(next, path[i]))
+					dir := g.CellDir(next, path[i])
+					if n := next.Neighbor(dir); n == nil || n.Index() != path[i].Index() {
+						// a tunnel and a passage on this path both wanted the same wall, leave the rest for another walk
+						for _, n := range path[:i+1] {
+							pathed[n.Index()] = false
+						}
+						break
+					}
+					g.ConnectCell(next, dir)
 					next = path[i]
 					visited[next.Index()] = true
 				}
@@ -1,6 +1,8 @@
 package algorithms
 
 import (
+	"fmt"
+	"github.com/bionoren/mazes/grid"
 	"math/rand"
 	"time"
 )
@@ -8,3 +10,13 @@ import (
 func init() {
 	rand.Seed(time.Now().Unix())
 }
+
+// requireRowsAndColumns panics unless g is laid out in rows and columns (and levels), which generators that go by which way a direction points need
+// on any other shape they'd leave the maze in pieces, or on a Planar maze, whose directions don't point anywhere, not touch it at all
+func requireRowsAndColumns(generator string, g grid.Maze) {
+	switch g.(type) {
+	case grid.Grid, grid.Upsilon, grid.Weave, grid.Grid3D:
+		return
+	}
+	panic(fmt.Sprintf("algorithms: %s only works on grids laid out in rows and columns, not on a %T", generator, g))
+}
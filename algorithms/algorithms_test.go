@@ -0,0 +1,153 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"github.com/faiface/pixel"
+	"math/rand"
+	"testing"
+)
+
+func TestRowsAndColumnsOnly(t *testing.T) {
+	generators := map[string]func(grid.Maze){
+		"BinarySearch": BinarySearch,
+		"Sidewinder":   Sidewinder,
+	}
+	mazes := []grid.Maze{
+		grid.NewCube(3),
+		grid.NewSphere(4),
+		grid.NewPlanar([]pixel.Vec{pixel.V(0, 0), pixel.V(4, 1), pixel.V(1, 3), pixel.V(5, 5)}),
+	}
+
+	for name, generate := range generators {
+		for _, m := range mazes {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected %s to refuse a %T", name, m)
+					}
+				}()
+				generate(m)
+			}()
+		}
+
+		// and still work on the shapes it's meant for
+		generate(grid.New(4, 5))
+		generate(grid.New3D(2, 3, 3))
+	}
+}
+
+func TestPerfect(t *testing.T) {
+	generators := map[string]func(grid.Maze){
+		"AldousBroder":         AldousBroder,
+		"AldousBroderWilsons":  AldousBroderWilsons,
+		"HuntAndKill":          HuntAndKill,
+		"Kruskals":             Kruskals,
+		"RecursiveBacktracker": RecursiveBacktracker,
+		"Wilsons":              Wilsons,
+	}
+	shapes := map[string]func() grid.Maze{
+		"flat":    func() grid.Maze { return grid.New(4, 5) },
+		"torus":   func() grid.Maze { return grid.NewTorus(4, 2) },
+		"klein":   func() grid.Maze { return grid.NewKlein(3, 4) },
+		"upsilon": func() grid.Maze { return grid.NewUpsilon(4, 4) },
+		"weave":   func() grid.Maze { return grid.NewWeave(4, 4) },
+		"3d":      func() grid.Maze { return grid.New3D(2, 3, 3) },
+		"cube":    func() grid.Maze { return grid.NewCube(2) },
+		"sphere":  func() grid.Maze { return grid.NewSphere(4) },
+	}
+
+	// small mazes, many times over, so the corners a generator can miss come up
+	for name, generate := range generators {
+		for shape, make := range shapes {
+			for i := 0; i < 500; i++ {
+				m := make()
+				generate(m)
+				if v := grid.Validate(m); !v.Perfect() {
+					t.Fatalf("%s on a %s grid: expected a perfect maze, found %d pieces and %d loops", name, shape, v.Components, v.Loops)
+				}
+			}
+		}
+	}
+}
+
+// braid links about fraction of the cells to one more of their neighbors, closing loops in a perfect maze
+func braid(g grid.Maze, random *rand.Rand, fraction float64) {
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+		if random.Float64() >= fraction {
+			continue
+		}
+		var closed []grid.Direction
+		for _, d := range g.Directions() {
+			if n := cell.Neighbor(d); n != nil && n.Index() != i && !cell.Connected(d) {
+				closed = append(closed, d)
+			}
+		}
+		if len(closed) > 0 {
+			g.ConnectCell(cell, closed[random.Intn(len(closed))])
+		}
+	}
+}
+
+// solverMaze is a maze for checking a solver on, along with the weights of its cells (nil unless it's weighted) and whether it's perfect
+type solverMaze struct {
+	name    string
+	maze    grid.Maze
+	weights []int
+	perfect bool
+}
+
+// solverMazes are perfect mazes of every shape, and the same with loops, portals and wrapped edges, for checking solvers against Dijkstra
+func solverMazes(random *rand.Rand) []solverMaze {
+	var points []pixel.Vec
+	for i := 0; i < 60; i++ {
+		points = append(points, pixel.V(random.Float64()*100, random.Float64()*100))
+	}
+	shapes := []struct {
+		name string
+		make func() grid.Maze
+	}{
+		{"flat", func() grid.Maze { return grid.New(8, 10) }},
+		{"narrow", func() grid.Maze { return grid.NewTorus(5, 2) }},
+		{"cylinder", func() grid.Maze { return grid.NewCylinder(6, 9) }},
+		{"torus", func() grid.Maze { return grid.NewTorus(8, 10) }},
+		{"mobius", func() grid.Maze { return grid.NewMobius(6, 9) }},
+		{"klein", func() grid.Maze { return grid.NewKlein(7, 9) }},
+		{"upsilon", func() grid.Maze { return grid.NewUpsilon(8, 8) }},
+		{"weave", func() grid.Maze { return grid.NewWeave(8, 8) }},
+		{"3d", func() grid.Maze { return grid.New3D(3, 5, 5) }},
+		{"cube", func() grid.Maze { return grid.NewCube(4) }},
+		{"sphere", func() grid.Maze { return grid.NewSphere(6) }},
+		{"planar", func() grid.Maze { return grid.NewPlanar(points) }},
+	}
+
+	var mazes []solverMaze
+	add := func(name string, m grid.Maze, weights []int, perfect bool) {
+		mazes = append(mazes, solverMaze{name, m, weights, perfect})
+	}
+	for _, shape := range shapes {
+		perfect := shape.make()
+		RecursiveBacktracker(perfect)
+		add(shape.name, perfect, nil, true)
+
+		braided := shape.make()
+		RecursiveBacktracker(braided)
+		braid(braided, random, 0.3)
+		add("braided "+shape.name, braided, nil, false)
+
+		weights := make([]int, braided.Size())
+		for i := range weights {
+			weights[i] = 1 + random.Intn(5)
+		}
+		add("weighted "+shape.name, braided, weights, false)
+	}
+
+	portals := grid.New(8, 10)
+	RecursiveBacktracker(portals)
+	for i := 0; i < 3; i++ {
+		grid.AddPortal(portals.Cell(random.Intn(8), random.Intn(10)), portals.Cell(random.Intn(8), random.Intn(10)))
+	}
+	add("portals", portals, nil, false)
+
+	return mazes
+}
@@ -0,0 +1,154 @@
+package algorithms
+
+import (
+	"container/heap"
+	"github.com/bionoren/mazes/grid"
+)
+
+// Heuristic estimates the cost of getting from a to b
+// it must never guess high, or AStar can miss the shortest path
+type Heuristic func(a, b grid.Cell) int
+
+// Manhattan counts the levels, rows and columns between two cells
+// it guesses high across wrapped edges and through portals, which can make AStar settle for a longer path
+func Manhattan(a, b grid.Cell) int {
+	return abs(a.Level()-b.Level()) + abs(a.Row()-b.Row()) + abs(a.Col()-b.Col())
+}
+
+// Chebyshev counts the levels between two cells, plus the rows or the columns between them, whichever there are more of
+// it's Manhattan for mazes with diagonal passages, which cross a row and a column in one step
+func Chebyshev(a, b grid.Cell) int {
+	rows, cols := abs(a.Row()-b.Row()), abs(a.Col()-b.Col())
+	if rows < cols {
+		rows = cols
+	}
+	return abs(a.Level()-b.Level()) + rows
+}
+
+// Zero guesses nothing, which makes AStar look at cells in the same order as Dijkstra
+// it's the only safe guess for mazes whose rows and columns don't measure distance, or that have shortcuts like portals or wrapped edges
+func Zero(a, b grid.Cell) int {
+	return 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// AStar finds the shortest path between two cells, looking at the cells that seem closest to the goal first
+// unlike Dijkstra, it stops as soon as it gets there, so it usually only has to look at part of the maze
+type AStar struct {
+	grid      grid.Maze
+	heuristic Heuristic
+	weights   []int
+}
+
+// NewAStar returns a solver for g guided by heuristic. If heuristic is nil it picks one that never guesses high from the maze's directions:
+// Manhattan for grids laid out in rows, columns and levels, Chebyshev for grids with diagonals, and Zero for everything else,
+// including weaves, whose tunnels cross two cells in one step, and mazes with wrapped edges or portals
+// portals added after the solver is made aren't noticed, so make a new one
+func NewAStar(g grid.Maze, heuristic Heuristic) AStar {
+	if heuristic == nil {
+		heuristic = defaultHeuristic(g)
+	}
+	return AStar{
+		grid:      g,
+		heuristic: heuristic,
+	}
+}
+
+// defaultHeuristic returns the heuristic NewAStar uses for g when it isn't given one
+func defaultHeuristic(g grid.Maze) Heuristic {
+	switch g.(type) {
+	case grid.Grid, grid.Upsilon, grid.Grid3D:
+	default:
+		return Zero
+	}
+
+	heuristic := Manhattan
+	for _, d := range g.Directions() {
+		switch {
+		case d.Tunnel():
+			return Zero
+		case d == grid.NORTHEAST || d == grid.SOUTHEAST || d == grid.SOUTHWEST || d == grid.NORTHWEST:
+			heuristic = Chebyshev
+		}
+	}
+
+	// a step across a wrapped edge or through a portal can cross the whole maze, which the guess has to allow for
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+		steps := cell.Portals()
+		for _, d := range g.Directions() {
+			if n := cell.Neighbor(d); n != nil {
+				steps = append(steps, *n)
+			}
+		}
+		for _, n := range steps {
+			if heuristic(cell, n) > 1 {
+				return Zero
+			}
+		}
+	}
+	return heuristic
+}
+
+// SetWeights sets the cost of stepping into each cell, indexed by cell index, like Dijkstra.SetWeights
+func (a *AStar) SetWeights(weights []int) {
+	a.weights = weights
+}
+
+func (a AStar) weight(idx int) int {
+	if a.weights == nil {
+		return 1
+	}
+	return a.weights[idx]
+}
+
+// Solve returns the shortest path from start to end as a slice of cell indexes starting from end, like Dijkstra.ShortestPath,
+// along with the number of cells it expanded on the way. The path is nil if end can't be reached from start
+func (a AStar) Solve(start, end grid.Cell) ([]int, int) {
+	// only the cells it gets to are kept track of, so a short search on a big maze stays cheap
+	costs := make(map[int]int) // cost of the cheapest way found to each cell so far
+	from := make(map[int]int)  // the cell each cell was reached from on that way
+	done := make(map[int]bool)
+
+	costs[start.Index()] = 0
+	from[start.Index()] = -1
+	queue := cellQueue{{cell: start, priority: a.heuristic(start, end)}}
+	var expanded int
+
+	for len(queue) > 0 {
+		item := heap.Pop(&queue).(queuedCell)
+		idx := item.cell.Index()
+		if done[idx] { // already reached more cheaply
+			continue
+		}
+		done[idx] = true
+		expanded++
+
+		if idx == end.Index() {
+			path := []int{idx}
+			for from[idx] >= 0 {
+				idx = from[idx]
+				path = append(path, idx)
+			}
+			return path, expanded
+		}
+
+		for _, next := range links(a.grid, item.cell) {
+			n := next.Index()
+			cost := costs[idx] + a.weight(n)
+			if known, ok := costs[n]; !done[n] && (!ok || cost < known) {
+				costs[n] = cost
+				from[n] = idx
+				heap.Push(&queue, queuedCell{cell: next, priority: cost + a.heuristic(next, end)})
+			}
+		}
+	}
+
+	return nil, expanded
+}
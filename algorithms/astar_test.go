@@ -0,0 +1,107 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+	"testing"
+)
+
+// checkPath checks that path leads from start to end (listed from end, like Dijkstra.ShortestPath) through links, and returns what it costs
+func checkPath(t *testing.T, name string, g grid.Maze, weights []int, path []int, start, end int) int {
+	t.Helper()
+	if len(path) == 0 || path[0] != end || path[len(path)-1] != start {
+		t.Fatalf("%s: path %v doesn't lead from %d to %d", name, path, start, end)
+	}
+	var cost int
+	for i := len(path) - 1; i > 0; i-- {
+		var linked bool
+		for _, next := range links(g, g.CellForIndex(path[i])) {
+			linked = linked || next.Index() == path[i-1]
+		}
+		if !linked {
+			t.Fatalf("%s: path %v steps from %d to %d, which aren't linked", name, path, path[i], path[i-1])
+		}
+		if weights == nil {
+			cost++
+		} else {
+			cost += weights[path[i-1]]
+		}
+	}
+	return cost
+}
+
+func TestAStar(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, test := range solverMazes(random) {
+		g := test.maze
+		solver := NewAStar(g, nil)
+		solver.SetWeights(test.weights)
+		d := NewDijkstra(g)
+		d.SetWeights(test.weights)
+
+		for i := 0; i < 20; i++ {
+			start, end := g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size()))
+			d.Init(start)
+			want := d.Distances()[end.Index()]
+
+			path, expanded := solver.Solve(start, end)
+			if cost := checkPath(t, test.name, g, test.weights, path, start.Index(), end.Index()); cost != want {
+				t.Fatalf("%s: A* found a path from %d to %d costing %d, Dijkstra found one costing %d", test.name, start.Index(), end.Index(), cost, want)
+			}
+
+			// it never has to look at a cell that's farther away than the goal
+			var closer int
+			for idx, dist := range d.Distances() {
+				if dist <= want && (dist > 0 || idx == start.Index()) {
+					closer++
+				}
+			}
+			if expanded < 1 || expanded > closer {
+				t.Fatalf("%s: A* expanded %d cells getting from %d to %d, but only %d are as close as the goal", test.name, expanded, start.Index(), end.Index(), closer)
+			}
+		}
+	}
+}
+
+func TestAStarUnreachable(t *testing.T) {
+	g := grid.New(4, 4)
+	g.ConnectCell(g.Cell(0, 0), grid.EAST)
+	g.ConnectCell(g.Cell(0, 1), grid.SOUTH)
+
+	path, expanded := NewAStar(g, nil).Solve(g.Cell(0, 0), g.Cell(3, 3))
+	if path != nil || expanded != 3 {
+		t.Fatalf("expected no path after expanding the 3 cells that can be reached, found %v after %d", path, expanded)
+	}
+}
+
+func TestDefaultHeuristic(t *testing.T) {
+	portals := grid.New(5, 5)
+	grid.AddPortal(portals.Cell(0, 0), portals.Cell(4, 4))
+	diagonals := grid.NewUpsilon(5, 5)
+
+	tests := []struct {
+		name string
+		maze grid.Maze
+		want Heuristic
+	}{
+		{"flat", grid.New(5, 5), Manhattan},
+		{"3d", grid.New3D(2, 5, 5), Manhattan},
+		{"upsilon", diagonals, Chebyshev},
+		{"cylinder", grid.NewCylinder(5, 5), Zero},
+		{"torus", grid.NewTorus(5, 5), Zero},
+		{"mobius", grid.NewMobius(5, 5), Zero},
+		{"klein", grid.NewKlein(5, 5), Zero},
+		{"narrow torus", grid.NewTorus(2, 2), Manhattan}, // nothing is more than a step away across its edges
+		{"portals", portals, Zero},
+		{"weave", grid.NewWeave(5, 5), Zero},
+		{"cube", grid.NewCube(3), Zero},
+	}
+	for _, test := range tests {
+		got := defaultHeuristic(test.maze)
+		// heuristics can't be compared, so compare what they guess between opposite corners
+		a, b := test.maze.CellForIndex(0), test.maze.CellForIndex(test.maze.Size()-1)
+		if got(a, b) != test.want(a, b) {
+			t.Errorf("%s: expected the default heuristic to guess %d between opposite corners, found %d", test.name, test.want(a, b), got(a, b))
+		}
+	}
+}
@@ -5,24 +5,27 @@ import (
 	"math/rand"
 )
 
-func BinarySearch(g grid.Grid) {
-	for r := 0; r < g.Rows(); r++ {
-		for c := 0; c < g.Cols(); c++ {
-			cell := g.Cell(r, c)
-			switch rand.Intn(2) {
-			case 0:
-				if cell.HasNeighbor(grid.WEST) {
-					g.Connect(r, c, grid.WEST)
-				} else if cell.HasNeighbor(grid.SOUTH) {
-					g.Connect(r, c, grid.SOUTH)
-				}
-			case 1:
-				if cell.HasNeighbor(grid.SOUTH) {
-					g.Connect(r, c, grid.SOUTH)
-				} else if cell.HasNeighbor(grid.WEST) {
-					g.Connect(r, c, grid.WEST)
-				}
-			}
+// BinarySearch links every cell west, south or down (on 3D grids), whichever of them it has
+// neighbors across a wrapped edge don't count, they would close loops
+// it only makes a perfect maze on grids laid out in rows and columns, so it panics on a Cube, Sphere or Planar maze
+func BinarySearch(g grid.Maze) {
+	requireRowsAndColumns("BinarySearch", g)
+	options := make([]grid.Direction, 0, 3)
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+
+		options = options[:0]
+		if n := cell.Neighbor(grid.WEST); n != nil && n.Col() < cell.Col() {
+			options = append(options, grid.WEST)
+		}
+		if n := cell.Neighbor(grid.SOUTH); n != nil && n.Row() > cell.Row() {
+			options = append(options, grid.SOUTH)
+		}
+		if cell.HasNeighbor(grid.DOWN) {
+			options = append(options, grid.DOWN)
+		}
+		if len(options) > 0 {
+			g.ConnectCell(cell, options[rand.Intn(len(options))])
 		}
 	}
 }
@@ -0,0 +1,36 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+)
+
+type queuedCell struct {
+	cell     grid.Cell
+	priority int
+}
+
+// cellQueue is a min-heap of cells, for use with container/heap
+type cellQueue []queuedCell
+
+func (q cellQueue) Len() int {
+	return len(q)
+}
+
+func (q cellQueue) Less(i, j int) bool {
+	return q[i].priority < q[j].priority
+}
+
+func (q cellQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *cellQueue) Push(x interface{}) {
+	*q = append(*q, x.(queuedCell))
+}
+
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -1,6 +1,7 @@
 package algorithms
 
 import (
+	"container/heap"
 	"github.com/bionoren/mazes/grid"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
@@ -14,12 +15,13 @@ import (
 type Dijkstra struct {
 	reference grid.Cell
 	distances []int
-	grid      grid.Grid
+	weights   []int
+	grid      grid.Maze
 }
 
-func NewDijkstra(g grid.Grid) Dijkstra {
+func NewDijkstra(g grid.Maze) Dijkstra {
 	return Dijkstra{
-		distances: make([]int, g.Rows()*g.Cols()),
+		distances: make([]int, g.Size()),
 		grid:      g,
 	}
 }
@@ -32,24 +34,65 @@ func (d Dijkstra) Distances() []int {
 	return d.distances
 }
 
+// SetWeights sets the cost of stepping into each cell, indexed by cell index. Every weight must be at least 1
+// without weights (or with nil weights) every cell costs 1. Call Init after changing the weights
+func (d *Dijkstra) SetWeights(weights []int) {
+	d.weights = weights
+}
+
+// LayerWeights reads cell weights for SetWeights from a layer of numbers
+// cells without a number, or with one less than 1, weigh 1
+func LayerWeights(g grid.Maze, layer string) []int {
+	weights := make([]int, g.Size())
+	for i := range weights {
+		weights[i] = 1
+		switch v := g.CellForIndex(i).Value(layer).(type) {
+		case int:
+			weights[i] = v
+		case float64:
+			weights[i] = int(v)
+		}
+		if weights[i] < 1 {
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
+func (d Dijkstra) Weights() []int {
+	return d.weights
+}
+
+// weight returns the cost of stepping into the cell at idx
+func (d Dijkstra) weight(idx int) int {
+	if d.weights == nil {
+		return 1
+	}
+	return d.weights[idx]
+}
+
 func (d *Dijkstra) Init(start grid.Cell) {
 	for i := range d.distances {
 		d.distances[i] = 0
 	}
 	d.reference = start
+	if d.weights != nil {
+		d.initWeighted()
+		return
+	}
 
-	queue := make([]*grid.Cell, 1, len(d.distances))
+	queue := make([]grid.Cell, 1, len(d.distances))
 	visited := make([]bool, len(d.distances)) // we consider a node "visited" when it is *added* to the queue, not when it is actually visited
 	visited[start.Index()] = true
-	queue[0] = &d.reference
+	queue[0] = d.reference
 
 	for len(queue) > 0 {
 		cell := queue[0]
 		queue = queue[1:]
 		dist := d.distances[cell.Index()] + 1
 
-		for dir := grid.NORTH; dir <= grid.WEST; dir++ {
-			if next := cell.Neighbor(dir); next != nil && cell.Connected(dir) && !visited[next.Index()] {
+		for _, next := range links(d.grid, cell) {
+			if !visited[next.Index()] {
 				d.distances[next.Index()] = dist
 				queue = append(queue, next)
 				visited[next.Index()] = true
@@ -58,24 +101,82 @@ func (d *Dijkstra) Init(start grid.Cell) {
 	}
 }
 
+// links returns the cells that can be reached from cell, through an opening or a portal
+func links(g grid.Maze, cell grid.Cell) []grid.Cell {
+	var cells []grid.Cell
+	for _, dir := range g.Directions() {
+		if next := cell.Neighbor(dir); next != nil && cell.Connected(dir) {
+			cells = append(cells, *next)
+		}
+	}
+	return append(cells, cell.Portals()...)
+}
+
+// linksInto returns the cells cell can be reached from, which differ from links when there are one-way passages
+func (d Dijkstra) linksInto(cell grid.Cell) []grid.Cell {
+	var cells []grid.Cell
+	for _, dir := range d.grid.Directions() {
+		if prev := cell.Neighbor(dir); prev != nil && prev.Connected(backDir(d.grid, cell, dir)) {
+			cells = append(cells, *prev)
+		}
+	}
+	return append(cells, cell.Portals()...)
+}
+
+// backDir returns the direction from cell's neighbor in dir back to cell
+// on a grid 2 cells across, the cells on either side are the same cell, so CellDir can't tell which side the way back is on
+func backDir(g grid.Maze, cell grid.Cell, dir grid.Direction) grid.Direction {
+	next := cell.Neighbor(dir)
+	if n := next.Neighbor(dir.Reverse()); n != nil && n.Index() == cell.Index() {
+		return dir.Reverse()
+	}
+	return g.CellDir(*next, cell)
+}
+
+// initWeighted fills in the distances from the reference cell, taking the cell weights into account
+func (d *Dijkstra) initWeighted() {
+	done := make([]bool, len(d.distances))
+	queue := cellQueue{{cell: d.reference}}
+
+	for len(queue) > 0 {
+		item := heap.Pop(&queue).(queuedCell)
+		if done[item.cell.Index()] { // already reached more cheaply
+			continue
+		}
+		done[item.cell.Index()] = true
+		d.distances[item.cell.Index()] = item.priority
+
+		for _, next := range links(d.grid, item.cell) {
+			if !done[next.Index()] {
+				heap.Push(&queue, queuedCell{cell: next, priority: item.priority + d.weight(next.Index())})
+			}
+		}
+	}
+}
+
 // ShortestPath returns the shortest path from the cell this was initialized with to the specified end cell
-// the return value is a slice of cell indexes
+// the return value is a slice of cell indexes, starting from end, or nil if end can't be reached
 func (d Dijkstra) ShortestPath(end grid.Cell) []int {
-	dist := d.distances[end.Index()]
-	path := make([]int, dist+1)
+	path := make([]int, 1, d.distances[end.Index()]+1)
 	path[0] = end.Index()
 
-	cell := &end
-	path[0] = cell.Index()
-	for i := 1; i < len(path); i++ {
-		for dir := grid.NORTH; dir <= grid.WEST; dir++ {
-			if next := cell.Neighbor(dir); next != nil && cell.Connected(dir) && d.distances[next.Index()] < dist {
-				path[i] = next.Index()
+	cell := end
+	for cell.Index() != d.reference.Index() {
+		// step back to whichever neighbor this cell was reached from
+		prev := d.distances[cell.Index()] - d.weight(cell.Index())
+		var found bool
+		for _, next := range d.linksInto(cell) {
+			// cells that can't be reached from the reference cell are left at 0 too
+			if d.distances[next.Index()] == prev && (prev > 0 || next.Index() == d.reference.Index()) {
+				path = append(path, next.Index())
 				cell = next
-				dist = d.distances[cell.Index()]
+				found = true
 				break
 			}
 		}
+		if !found { // end can't be reached
+			return nil
+		}
 	}
 
 	return path
@@ -85,7 +186,7 @@ func (d Dijkstra) ShortestPath(end grid.Cell) []int {
 // the return value is a slice of cell indexes
 func (d Dijkstra) LongestPath() []int {
 	var maxDistance int
-	var maxIndex int
+	maxIndex := d.reference.Index()
 	for i, dist := range d.distances {
 		if dist > maxDistance {
 			maxIndex = i
@@ -104,22 +205,16 @@ func (d Dijkstra) Draw(window pixel.Target, size pixel.Rect, thickness float64,
 
 	target := imdraw.New(nil)
 
-	cellWidth := (size.W() - thickness) / float64(d.grid.Cols())
-	cellHeight := (size.H() - thickness) / float64(d.grid.Rows())
-
 	basicAtlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
 	labelWriter := text.New(pixel.ZV, basicAtlas)
 	labelWriter.Color = color.White
 
-	for r := 0; r < d.grid.Rows(); r++ {
-		y := float64(d.grid.Rows()-r)*cellHeight + thickness*2 // top left
-		for c := 0; c < d.grid.Cols(); c++ {
-			x := float64(c)*cellWidth + thickness // top left
-			cell := d.grid.Cell(r, c)
+	for i := 0; i < d.grid.Size(); i++ {
+		cell := d.grid.CellForIndex(i)
+		rect := d.grid.CellRect(cell, size, thickness)
 
-			labelWriter.Dot = pixel.V(x+thickness, y-cellHeight/2)
-			_, _ = labelWriter.WriteString(strconv.Itoa(d.distances[cell.Index()]))
-		}
+		labelWriter.Dot = pixel.V(rect.Min.X+thickness, rect.Center().Y)
+		_, _ = labelWriter.WriteString(strconv.Itoa(d.distances[cell.Index()]))
 	}
 
 	target.Draw(window)
@@ -147,9 +242,6 @@ func (d Dijkstra) DrawLongestPath(window pixel.Target, size pixel.Rect, thicknes
 func (d Dijkstra) Fill(window pixel.Target, size pixel.Rect, thickness float64) {
 	target := imdraw.New(nil)
 
-	cellWidth := (size.W() - thickness) / float64(d.grid.Cols())
-	cellHeight := (size.H() - thickness) / float64(d.grid.Rows())
-
 	var maxDistance int
 	for _, dist := range d.distances {
 		if dist > maxDistance {
@@ -157,40 +249,36 @@ func (d Dijkstra) Fill(window pixel.Target, size pixel.Rect, thickness float64)
 		}
 	}
 
-	for r := 0; r < d.grid.Rows(); r++ {
-		y := float64(d.grid.Rows()-r)*cellHeight + thickness*2 // top left
-		for c := 0; c < d.grid.Cols(); c++ {
-			x := float64(c)*cellWidth + thickness // top left
-			cell := d.grid.Cell(r, c)
-
-			colorVal := uint8(math.Round(255 * float64(maxDistance-d.distances[cell.Index()]) / float64(maxDistance)))
-			target.Color = color.RGBA{
-				R: colorVal,
-				G: colorVal,
-				B: colorVal,
-			}
+	for i := 0; i < d.grid.Size(); i++ {
+		cell := d.grid.CellForIndex(i)
+		rect := d.grid.CellRect(cell, size, thickness)
 
-			target.Push(pixel.V(x, y), pixel.V(x+cellWidth, y-cellHeight))
-			target.Rectangle(0)
+		colorVal := uint8(math.Round(255 * float64(maxDistance-d.distances[cell.Index()]) / float64(maxDistance)))
+		target.Color = color.RGBA{
+			R: colorVal,
+			G: colorVal,
+			B: colorVal,
 		}
+
+		target.Push(rect.Min, rect.Max)
+		target.Rectangle(0)
 	}
 
 	target.Draw(window)
 }
 
 func (d Dijkstra) drawPath(path []int, window pixel.Target, size pixel.Rect, thickness float64, pathColor color.Color) {
+	drawPath(d.grid, path, window, size, thickness, pathColor)
+}
+
+// drawPath draws a line through the centers of the cells in path
+func drawPath(g grid.Maze, path []int, window pixel.Target, size pixel.Rect, thickness float64, pathColor color.Color) {
 	target := imdraw.New(nil)
 	target.Color = pathColor
 
-	cellWidth := (size.W() - thickness) / float64(d.grid.Cols())
-	cellHeight := (size.H() - thickness) / float64(d.grid.Rows())
-
 	for _, idx := range path {
-		cell := d.grid.CellForIndex(idx)
-		x := float64(cell.Col())*cellWidth + thickness                  // top left
-		y := float64(d.grid.Rows()-cell.Row())*cellHeight + thickness*2 // top left
-
-		target.Push(pixel.V(x+cellWidth/2, y-cellHeight/2))
+		cell := g.CellForIndex(idx)
+		target.Push(g.CellRect(cell, size, thickness).Center())
 	}
 
 	target.Line(thickness)
@@ -0,0 +1,25 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathNarrow(t *testing.T) {
+	// on a cylinder 2 cells across, a cell's east and west neighbors are the same cell, and these are only linked on one side
+	g := grid.NewCylinder(2, 2)
+	g.ConnectCell(g.Cell(0, 0), grid.WEST)
+	g.ConnectCell(g.Cell(0, 0), grid.SOUTH)
+	g.ConnectCell(g.Cell(1, 0), grid.EAST)
+
+	d := NewDijkstra(g)
+	d.Init(g.Cell(0, 1))
+	if path, want := d.ShortestPath(g.Cell(1, 1)), []int{3, 2, 0, 1}; !reflect.DeepEqual(path, want) {
+		t.Fatalf("expected the path %v, found %v", want, path)
+	}
+	d.Init(g.Cell(1, 1))
+	if path, want := d.ShortestPath(g.Cell(0, 1)), []int{1, 0, 2, 3}; !reflect.DeepEqual(path, want) {
+		t.Fatalf("expected the path %v, found %v", want, path)
+	}
+}
@@ -0,0 +1,192 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+)
+
+// Room is a rectangle of cells in a dungeon that are all linked to each other
+type Room struct {
+	Row, Col   int // top left
+	Rows, Cols int
+}
+
+// Contains reports whether the cell at row, col is inside the room
+func (r Room) Contains(row, col int) bool {
+	return row >= r.Row && row < r.Row+r.Rows && col >= r.Col && col < r.Col+r.Cols
+}
+
+// overlaps reports whether the rooms overlap or touch, which would leave no wall between them
+func (r Room) overlaps(other Room) bool {
+	return r.Row <= other.Row+other.Rows && other.Row <= r.Row+r.Rows && r.Col <= other.Col+other.Cols && other.Col <= r.Col+r.Cols
+}
+
+type DungeonSettings struct {
+	Rooms            int     // rooms to try to place. Fewer may fit
+	MinSize, MaxSize int     // the range of room widths and heights. Rooms are at least 1 cell across, and MaxSize is at least MinSize
+	ExtraDoors       float64 // chance of giving each room another door, which makes loops
+	RemoveDeadEnds   bool    // fill in corridors that don't lead anywhere
+}
+
+// Dungeon places rooms that don't overlap, fills the space between them with a maze of corridors,
+// then opens doors until every room and corridor is connected. It returns the rooms it placed
+func Dungeon(g grid.Grid, settings DungeonSettings) []Room {
+	if settings.MinSize < 1 {
+		settings.MinSize = 1
+	}
+	if settings.MaxSize < settings.MinSize {
+		settings.MaxSize = settings.MinSize
+	}
+
+	var rooms []Room
+	inRoom := make([]bool, g.Size())
+	for attempt := 0; attempt < settings.Rooms*10 && len(rooms) < settings.Rooms; attempt++ {
+		room := Room{
+			Rows: settings.MinSize + rand.Intn(settings.MaxSize-settings.MinSize+1),
+			Cols: settings.MinSize + rand.Intn(settings.MaxSize-settings.MinSize+1),
+		}
+		if room.Rows > g.Rows() || room.Cols > g.Cols() {
+			continue
+		}
+		room.Row = rand.Intn(g.Rows() - room.Rows + 1)
+		room.Col = rand.Intn(g.Cols() - room.Cols + 1)
+
+		fits := true
+		for _, other := range rooms {
+			if room.overlaps(other) {
+				fits = false
+				break
+			}
+		}
+		if !fits {
+			continue
+		}
+
+		rooms = append(rooms, room)
+		for r := room.Row; r < room.Row+room.Rows; r++ {
+			for c := room.Col; c < room.Col+room.Cols; c++ {
+				inRoom[g.Cell(r, c).Index()] = true
+				if c+1 < room.Col+room.Cols {
+					g.Connect(r, c, grid.EAST)
+				}
+				if r+1 < room.Row+room.Rows {
+					g.Connect(r, c, grid.SOUTH)
+				}
+			}
+		}
+	}
+
+	corridors(g, inRoom)
+
+	// doors join each room to the corridors around it, like Kruskals, so everything ends up connected
+	sets := newDisjointSets(g)
+	type door struct {
+		cell grid.Cell
+		dir  grid.Direction
+	}
+	doors := make([][]door, len(rooms))
+	var all []door
+	for i, room := range rooms {
+		for r := room.Row; r < room.Row+room.Rows; r++ {
+			for c := room.Col; c < room.Col+room.Cols; c++ {
+				cell := g.Cell(r, c)
+				for _, d := range g.Directions() {
+					if n := cell.Neighbor(d); n != nil && !room.Contains(n.Row(), n.Col()) {
+						doors[i] = append(doors[i], door{cell, d})
+					}
+				}
+			}
+		}
+		all = append(all, doors[i]...)
+	}
+	rand.Shuffle(len(all), func(i, j int) {
+		all[i], all[j] = all[j], all[i]
+	})
+	for _, d := range all {
+		if sets.union(d.cell.Index(), d.cell.Neighbor(d.dir).Index()) {
+			g.ConnectCell(d.cell, d.dir)
+		}
+	}
+	for i := range rooms {
+		if len(doors[i]) > 0 && rand.Float64() < settings.ExtraDoors {
+			d := doors[i][rand.Intn(len(doors[i]))]
+			g.ConnectCell(d.cell, d.dir)
+		}
+	}
+
+	if settings.RemoveDeadEnds {
+		removeDeadEnds(g, inRoom)
+	}
+
+	return rooms
+}
+
+// corridors carves a maze through every cell that isn't in a room, without breaking into any room
+func corridors(g grid.Grid, inRoom []bool) {
+	visited := make([]bool, g.Size())
+	copy(visited, inRoom)
+
+	for idx := 0; idx < g.Size(); idx++ {
+		if visited[idx] {
+			continue
+		}
+		visited[idx] = true
+
+		// a recursive backtracker from here, which stops at rooms and fills this stretch between them
+		stack := []grid.Cell{g.CellForIndex(idx)}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			var options []grid.Direction
+			for _, d := range g.Directions() {
+				if n := node.Neighbor(d); n != nil && !visited[n.Index()] {
+					options = append(options, d)
+				}
+			}
+			if len(options) == 0 {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			dir := options[rand.Intn(len(options))]
+			next := node.Neighbor(dir)
+			visited[next.Index()] = true
+			g.ConnectCell(node, dir)
+			stack = append(stack, *next)
+		}
+	}
+}
+
+// removeDeadEnds walls off corridor cells with only one way out until none are left
+// the cells it empties out are left unlinked, as solid rock
+func removeDeadEnds(g grid.Grid, inRoom []bool) {
+	exits := func(cell grid.Cell) []grid.Direction {
+		var dirs []grid.Direction
+		for _, d := range g.Directions() {
+			if cell.Connected(d) {
+				dirs = append(dirs, d)
+			}
+		}
+		return dirs
+	}
+
+	var deadEnds []grid.Cell
+	for idx := 0; idx < g.Size(); idx++ {
+		if cell := g.CellForIndex(idx); !inRoom[idx] && len(exits(cell)) == 1 {
+			deadEnds = append(deadEnds, cell)
+		}
+	}
+	for len(deadEnds) > 0 {
+		cell := deadEnds[len(deadEnds)-1]
+		deadEnds = deadEnds[:len(deadEnds)-1]
+		dirs := exits(cell)
+		if len(dirs) != 1 {
+			continue
+		}
+
+		next := cell.Neighbor(dirs[0])
+		g.Disconnect(cell.Row(), cell.Col(), dirs[0])
+		if !inRoom[next.Index()] && len(exits(*next)) == 1 {
+			deadEnds = append(deadEnds, *next)
+		}
+	}
+}
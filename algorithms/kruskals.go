@@ -0,0 +1,117 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+)
+
+// Kruskals knocks down random walls between cells that aren't already connected to each other until every cell is connected
+// links already in the grid are kept, so it can finish a maze that was partially seeded by hand (see WeaveKruskals)
+func Kruskals(g grid.Maze) {
+	kruskals(g, rand.Shuffle)
+}
+
+// kruskals is Kruskals with the walls put in order by shuffle, so it can be driven by its own random source
+func kruskals(g grid.Maze, shuffle func(n int, swap func(i, j int))) {
+	sets := newDisjointSets(g)
+
+	type wall struct {
+		cell grid.Cell
+		dir  grid.Direction
+	}
+	walls := make([]wall, 0, g.Size()*2)
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+		for _, d := range g.Directions() {
+			if n := cell.Neighbor(d); n != nil && !d.Tunnel() && n.Index() > i {
+				walls = append(walls, wall{cell, d})
+			}
+		}
+	}
+	shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	for _, w := range walls {
+		// grab a fresh copy; earlier links may have claimed this side for a tunnel
+		cell := g.CellForIndex(w.cell.Index())
+		if n := cell.Neighbor(w.dir); n != nil && sets.union(cell.Index(), n.Index()) {
+			g.ConnectCell(cell, w.dir)
+		}
+	}
+}
+
+// WeaveKruskals lays down random crossings (up to crossings of them) before running Kruskals over the rest of the weave
+func WeaveKruskals(w grid.Weave, crossings int) {
+	sets := newDisjointSets(w)
+	size := w.Size()
+
+	for i := 0; i < crossings; i++ {
+		cell := w.CellForIndex(rand.Intn(size))
+		north, east, south, west := cell.Neighbor(grid.NORTH), cell.Neighbor(grid.EAST), cell.Neighbor(grid.SOUTH), cell.Neighbor(grid.WEST)
+		if north == nil || east == nil || south == nil || west == nil {
+			continue
+		}
+		// a crossing merges the sets of all five cells, which would close a loop if any two of them were already joined
+		roots := map[int]bool{}
+		for _, c := range []*grid.Cell{&cell, north, east, south, west} {
+			roots[sets.find(c.Index())] = true
+		}
+		if len(roots) < 5 {
+			continue
+		}
+
+		over := grid.EAST
+		if rand.Intn(2) == 0 {
+			over = grid.NORTH
+			east, north = north, east
+			west, south = south, west
+		}
+		if w.Cross(cell.Row(), cell.Col(), over) {
+			sets.union(cell.Index(), east.Index())
+			sets.union(cell.Index(), west.Index())
+			sets.union(north.Index(), south.Index())
+		}
+	}
+
+	Kruskals(w)
+}
+
+// disjointSets is a union-find over cell indexes
+type disjointSets []int
+
+// newDisjointSets puts every cell in its own set, then merges cells that are already linked
+func newDisjointSets(g grid.Maze) disjointSets {
+	sets := make(disjointSets, g.Size())
+	for i := range sets {
+		sets[i] = i
+	}
+	for i := range sets {
+		cell := g.CellForIndex(i)
+		for _, d := range g.Directions() {
+			if n := cell.Neighbor(d); n != nil && cell.Connected(d) {
+				sets.union(i, n.Index())
+			}
+		}
+	}
+
+	return sets
+}
+
+func (s disjointSets) find(i int) int {
+	for s[i] != i {
+		s[i] = s[s[i]]
+		i = s[i]
+	}
+	return i
+}
+
+// union merges the sets containing a and b, returning false if they were already the same set
+func (s disjointSets) union(a, b int) bool {
+	a, b = s.find(a), s.find(b)
+	if a == b {
+		return false
+	}
+	s[b] = a
+	return true
+}
@@ -5,20 +5,20 @@ import (
 	"math/rand"
 )
 
-func RecursiveBacktracker(g grid.Grid) {
-	size := g.Rows() * g.Cols()
+func RecursiveBacktracker(g grid.Maze) {
+	size := g.Size()
 	node := g.CellForIndex(rand.Intn(size))
 	visited := make([]bool, size)
 	visited[node.Index()] = true
 
-	directions := make([]grid.Direction, (grid.WEST+1)*2)
-	for d := grid.NORTH; d <= grid.WEST; d++ {
-		directions[d] = d
-	}
-	dirOptions := directions[grid.WEST+1:]
-	directions = directions[:grid.WEST+1]
+	dirs := g.Directions()
+	directions := make([]grid.Direction, len(dirs)*2)
+	copy(directions, dirs)
+	copy(directions[len(dirs):], dirs)
+	dirOptions := directions[len(dirs):]
+	directions = directions[:len(dirs)]
 
-	stack := make([]grid.Cell, 1, g.Cols()*g.Rows()/2)
+	stack := make([]grid.Cell, 1, size/2)
 	stack[0] = node
 	for len(stack) > 0 {
 		// fmt.Printf("stack size: %d, dirOptions size: %d\n", len(stack), len(dirOptions))
@@ -27,7 +27,7 @@ func RecursiveBacktracker(g grid.Grid) {
 			dir := dirOptions[i]
 			if next := node.Neighbor(dir); next != nil && !visited[next.Index()] {
 				visited[next.Index()] = true
-				g.Connect(node.Row(), node.Col(), dir)
+				g.ConnectCell(node, dir)
 
 				node = *next
 				dirOptions = dirOptions[:cap(dirOptions)]
@@ -37,9 +37,11 @@ func RecursiveBacktracker(g grid.Grid) {
 				dirOptions[i] = dirOptions[len(dirOptions)-1]
 				dirOptions = dirOptions[:len(dirOptions)-1]
 			}
-		} else { // backtrack
-			node = stack[len(stack)-1]
+		} else { // backtrack. The node stays on the stack until it's out of options, so it gets back to any it skipped
 			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				node = stack[len(stack)-1]
+			}
 
 			dirOptions = dirOptions[:cap(dirOptions)]
 			copy(dirOptions, directions)
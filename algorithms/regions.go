@@ -0,0 +1,213 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+)
+
+// Regions splits a maze into connected groups of cells, labeling each cell with the region it's in
+type Regions struct {
+	labels []int // region of each cell by index, or -1 for cells that aren't in one
+	count  int
+	grid   grid.Maze
+}
+
+// GrowRegions grows a region from each seed cell, a step at a time in turn, until every cell that can be reached from a seed is taken
+// region i is the one grown from seeds[i]
+func GrowRegions(g grid.Maze, seeds []grid.Cell) Regions {
+	r := newRegions(g, len(seeds))
+
+	frontiers := make([][]grid.Cell, len(seeds))
+	for i, seed := range seeds {
+		if r.labels[seed.Index()] < 0 {
+			r.labels[seed.Index()] = i
+			frontiers[i] = []grid.Cell{seed}
+		}
+	}
+
+	for growing := true; growing; {
+		growing = false
+		for i, frontier := range frontiers {
+			var next []grid.Cell
+			for _, cell := range frontier {
+				for _, n := range adjacent(g, cell) {
+					if r.labels[n.Index()] < 0 {
+						r.labels[n.Index()] = i
+						next = append(next, n)
+					}
+				}
+			}
+			frontiers[i] = next
+			growing = growing || len(next) > 0
+		}
+	}
+
+	return r
+}
+
+// CutRegions splits a perfect maze into (about) n regions of similar size by cutting its spanning tree
+// mazes with loops are cut along a spanning tree of their links. Each group of cells that aren't connected to each other is cut separately
+// n less than 1 leaves each of those groups whole
+func CutRegions(g grid.Maze, n int) Regions {
+	if n < 1 {
+		n = 1
+	}
+	r := newRegions(g, 0)
+	target := (g.Size() + n - 1) / n
+	if target < 1 {
+		target = 1
+	}
+
+	for root := 0; root < g.Size(); root++ {
+		if r.labels[root] >= 0 {
+			continue
+		}
+
+		// a breadth first spanning tree, so every cell comes after its parent in order
+		parents := map[int]int{root: -1}
+		order := []grid.Cell{g.CellForIndex(root)}
+		r.labels[root] = 0 // marks the cell as seen until it's given a real label
+		for i := 0; i < len(order); i++ {
+			for _, next := range adjacent(g, order[i]) {
+				if r.labels[next.Index()] < 0 {
+					r.labels[next.Index()] = 0
+					parents[next.Index()] = order[i].Index()
+					order = append(order, next)
+				}
+			}
+		}
+
+		// working up from the leaves, cut off each subtree as soon as it's big enough
+		sizes := make(map[int]int, len(order))
+		cut := make(map[int]bool)
+		for i := len(order) - 1; i >= 0; i-- {
+			idx := order[i].Index()
+			sizes[idx]++
+			if sizes[idx] >= target || parents[idx] < 0 {
+				cut[idx] = true
+			} else {
+				sizes[parents[idx]] += sizes[idx]
+			}
+		}
+
+		// each cut subtree becomes a region, without the subtrees cut off below it
+		for _, cell := range order {
+			idx := cell.Index()
+			if cut[idx] {
+				r.labels[idx] = r.count
+				r.count++
+			} else {
+				r.labels[idx] = r.labels[parents[idx]]
+			}
+		}
+	}
+
+	return r
+}
+
+func newRegions(g grid.Maze, count int) Regions {
+	labels := make([]int, g.Size())
+	for i := range labels {
+		labels[i] = -1
+	}
+
+	return Regions{
+		labels: labels,
+		count:  count,
+		grid:   g,
+	}
+}
+
+// adjacent returns the cells linked to cell, whichever way the passages between them can be taken, including through portals
+func adjacent(g grid.Maze, cell grid.Cell) []grid.Cell {
+	var cells []grid.Cell
+	for _, dir := range g.Directions() {
+		if n := cell.Neighbor(dir); n != nil && (cell.Connected(dir) || n.Connected(g.CellDir(*n, cell))) {
+			cells = append(cells, *n)
+		}
+	}
+	return append(cells, cell.Portals()...)
+}
+
+// Count returns the number of regions
+func (r Regions) Count() int {
+	return r.count
+}
+
+// Label returns the region the cell is in, or -1 if it isn't in one
+func (r Regions) Label(cell grid.Cell) int {
+	return r.labels[cell.Index()]
+}
+
+// Labels returns the region of every cell by index
+func (r Regions) Labels() []int {
+	return r.labels
+}
+
+// Cells returns the cells in a region
+func (r Regions) Cells(region int) []grid.Cell {
+	var cells []grid.Cell
+	for i, label := range r.labels {
+		if label == region {
+			cells = append(cells, r.grid.CellForIndex(i))
+		}
+	}
+	return cells
+}
+
+// Layer saves each cell's region in the layer of the maze with the given name, so the regions travel with it, and returns the layer
+// cells that aren't in a region are left without a value. Draw the layer with Color to see the regions
+func (r Regions) Layer(name string) grid.Layer {
+	layer := grid.LayerOf(r.grid, name)
+	layer.Clear()
+	for i, label := range r.labels {
+		if label >= 0 {
+			layer.Set(r.grid.CellForIndex(i), label)
+		}
+	}
+	return layer
+}
+
+// Color returns a color for a region that stands out from the regions numbered near it
+func (r Regions) Color(region int) color.RGBA {
+	if region < 0 {
+		return color.RGBA{A: 255}
+	}
+
+	// step around the color wheel by the golden angle, so neighboring labels get very different hues
+	hue := math.Mod(float64(region)*137.508, 360) / 60
+	x := uint8(math.Round(200 * (1 - math.Abs(math.Mod(hue, 2)-1))))
+	switch int(hue) {
+	case 0:
+		return color.RGBA{R: 200, G: x, A: 255}
+	case 1:
+		return color.RGBA{R: x, G: 200, A: 255}
+	case 2:
+		return color.RGBA{G: 200, B: x, A: 255}
+	case 3:
+		return color.RGBA{G: x, B: 200, A: 255}
+	case 4:
+		return color.RGBA{R: x, B: 200, A: 255}
+	default:
+		return color.RGBA{R: 200, B: x, A: 255}
+	}
+}
+
+// Draw fills in each cell with the color of its region
+func (r Regions) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+
+	for i := 0; i < r.grid.Size(); i++ {
+		cell := r.grid.CellForIndex(i)
+		rect := r.grid.CellRect(cell, size, thickness)
+
+		target.Color = r.Color(r.labels[i])
+		target.Push(rect.Min, rect.Max)
+		target.Rectangle(0)
+	}
+
+	target.Draw(window)
+}
@@ -5,30 +5,32 @@ import (
 	"math/rand"
 )
 
-func Sidewinder(g grid.Grid) {
-	var runStart int
-	for r := 0; r < g.Rows(); r++ {
-		for c := 0; c < g.Cols(); c++ {
-			cell := g.Cell(r, c)
-			switch rand.Intn(2) {
-			case 0: // continue the run
-				if cell.HasNeighbor(grid.EAST) {
-					g.Connect(r, c, grid.EAST)
-				}
-			case 1:
-				if cell.HasNeighbor(grid.NORTH) {
-					idx := rand.Intn(c-runStart+1) + runStart
-					g.Connect(r, idx, grid.NORTH)
-				} else if cell.HasNeighbor(grid.EAST) {
-					g.Connect(r, c, grid.EAST)
-				}
-				runStart = c + 1
-			}
+// Sidewinder carves runs of cells eastward along each row, closing each run north from a random cell in it
+// rows along the north edge of a 3D grid's upper levels close their runs down instead
+// neighbors across a wrapped edge are ignored, so runs stop at the end of the row
+// it only makes a perfect maze on grids laid out in rows and columns, so it panics on a Cube, Sphere or Planar maze
+func Sidewinder(g grid.Maze) {
+	requireRowsAndColumns("Sidewinder", g)
+	run := make([]grid.Cell, 0)
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+		run = append(run, cell)
+
+		out := grid.NORTH
+		if n := cell.Neighbor(out); n == nil || n.Row() > cell.Row() {
+			out = grid.DOWN
+		}
+		canClose := cell.HasNeighbor(out)
+		east := cell.Neighbor(grid.EAST)
+		canContinue := east != nil && east.Col() > cell.Col()
+
+		if canContinue && (!canClose || rand.Intn(2) == 0) { // continue the run
+			g.ConnectCell(cell, grid.EAST)
+			continue
 		}
-		if runStart < g.Cols() && g.Cell(r, 0).HasNeighbor(grid.NORTH) {
-			idx := rand.Intn(g.Cols()-runStart) + runStart
-			g.Connect(r, idx, grid.NORTH)
+		if canClose {
+			g.ConnectCell(run[rand.Intn(len(run))], out)
 		}
-		runStart = 0
+		run = run[:0]
 	}
 }
@@ -0,0 +1,110 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+)
+
+// TremauxWalk is a Walk along with the marks Trémaux's algorithm left on the passages it took
+type TremauxWalk struct {
+	Walk
+	// Marks counts the times each passage was walked, at most twice
+	Marks map[Passage]int
+}
+
+// Passage names a passage by the cells at its ends, lower index first, and the way out of the first cell into it
+// two cells can have more than one passage between them, like the cells of a row of a grid 2 cells across, which meet on both sides
+// a portal's Dir is -1, since there's only ever one portal between two cells
+type Passage struct {
+	From, To int
+	Dir      grid.Direction
+}
+
+// newPassage returns the passage out of cell in dir
+func newPassage(g grid.Maze, cell grid.Cell, dir grid.Direction) Passage {
+	from, to := cell.Index(), cell.Neighbor(dir).Index()
+	if back := backDir(g, cell, dir); to < from || (to == from && back < dir) {
+		return Passage{to, from, back}
+	}
+	return Passage{from, to, dir}
+}
+
+// portalPassage returns the passage through the portal between two cells
+func portalPassage(a, b grid.Cell) Passage {
+	if b.Index() < a.Index() {
+		a, b = b, a
+	}
+	return Passage{a.Index(), b.Index(), -1}
+}
+
+// Tremaux walks from start to end marking each passage as it goes, the way a person could with a piece of chalk
+// coming into a new cell it takes a passage it hasn't walked yet. Coming back to a cell it's already been to down a new passage,
+// it turns around. Otherwise it takes the passage it's walked the least, and never walks one more than twice
+// unlike a wall follower it always finds the end if it can be reached, even in mazes with loops. The passages marked once lead from start to end
+// portals are taken like any other passage. One-way passages are only walked the way they go, which can leave it stranded,
+// since it counts on being able to walk back down any passage it took
+func Tremaux(g grid.Maze, start, end grid.Cell) TremauxWalk {
+	walk := TremauxWalk{
+		Walk:  Walk{Trace: []int{start.Index()}},
+		Marks: make(map[Passage]int),
+	}
+
+	// way is a passage out of a cell, and the cell it leads to
+	type way struct {
+		passage Passage
+		to      grid.Cell
+	}
+	var options []way
+
+	visited := make([]bool, g.Size())
+	cell, came := start, Passage{-1, -1, -1} // came is the passage just walked down
+	for cell.Index() != end.Index() {
+		options = options[:0]
+		for _, d := range g.Directions() {
+			if n := cell.Neighbor(d); n != nil && cell.Connected(d) {
+				options = append(options, way{newPassage(g, cell, d), *n})
+			}
+		}
+		for _, n := range cell.Portals() {
+			options = append(options, way{portalPassage(cell, n), n})
+		}
+
+		next := -1
+		if visited[cell.Index()] && came.From >= 0 && walk.Marks[came] == 1 {
+			// been here before, and just found another way in: go back the way we came
+			for i, o := range options {
+				if o.passage == came {
+					next = i
+				}
+			}
+		}
+		if next < 0 {
+			for i, o := range options {
+				marks := walk.Marks[o.passage]
+				if marks >= 2 {
+					continue
+				}
+				if next < 0 {
+					next = i
+					continue
+				}
+				// fewer marks first, then anywhere but back the way we came
+				best := walk.Marks[options[next].passage]
+				if marks < best || (marks == best && options[next].passage == came) {
+					next = i
+				}
+			}
+		}
+		if next < 0 { // every way out has been walked twice, so end can't be reached
+			break
+		}
+
+		visited[cell.Index()] = true
+		walk.Marks[options[next].passage]++
+		came, cell = options[next].passage, options[next].to
+		walk.Trace = append(walk.Trace, cell.Index())
+	}
+
+	walk.Solved = cell.Index() == end.Index()
+	walk.Path = reduce(walk.Trace)
+	return walk
+}
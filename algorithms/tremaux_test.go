@@ -0,0 +1,78 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+	"testing"
+)
+
+// checkMarks checks that no passage of a Trémaux walk was walked more than twice, and that every step of its trace left a mark
+func checkMarks(t *testing.T, name string, walk TremauxWalk) {
+	t.Helper()
+	var steps int
+	for p, marks := range walk.Marks {
+		if marks < 1 || marks > 2 {
+			t.Fatalf("%s: passage %+v has %d marks", name, p, marks)
+		}
+		steps += marks
+	}
+	if steps != len(walk.Trace)-1 {
+		t.Fatalf("%s: the walk took %d steps, but left %d marks", name, len(walk.Trace)-1, steps)
+	}
+}
+
+func TestTremaux(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, test := range solverMazes(random) {
+		g := test.maze
+		d := NewDijkstra(g)
+		for i := 0; i < 10; i++ {
+			start, end := g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size()))
+			d.Init(start)
+
+			walk := Tremaux(g, start, end)
+			checkMarks(t, test.name, walk)
+			checkSteps(t, test.name, g, walk.Trace)
+			checkSteps(t, test.name, g, walk.Path)
+			if !walk.Solved || walk.Path[0] != start.Index() || walk.Path[len(walk.Path)-1] != end.Index() {
+				t.Fatalf("%s: expected the walk to find its way from %d to %d, found %+v", test.name, start.Index(), end.Index(), walk)
+			}
+			if test.perfect && len(walk.Path) != len(d.ShortestPath(end)) {
+				t.Fatalf("%s: expected the path from %d to %d to be the only one, %v, found %v", test.name, start.Index(), end.Index(), d.ShortestPath(end), walk.Path)
+			}
+		}
+	}
+}
+
+func TestTremauxSides(t *testing.T) {
+	// the cells of each row of a cylinder 2 cells across meet on both sides, and the rows are joined by a passage and a portal side by side
+	g := grid.NewCylinder(3, 2)
+	for r := 0; r < 3; r++ {
+		g.ConnectCell(g.Cell(r, 0), grid.EAST)
+		g.ConnectCell(g.Cell(r, 0), grid.WEST)
+	}
+	g.ConnectCell(g.Cell(0, 1), grid.SOUTH)
+	grid.AddPortal(g.Cell(0, 1), g.Cell(1, 1))
+
+	// the bottom row can't be reached, so every passage that can be is walked both ways before the walk gives up
+	walk := Tremaux(g, g.Cell(0, 0), g.Cell(2, 1))
+	checkMarks(t, "unreachable", walk)
+	if walk.Solved {
+		t.Fatalf("expected the walk not to reach the bottom row, found %+v", walk)
+	}
+	if len(walk.Marks) != 6 {
+		t.Fatalf("expected the walk to mark both sides of the top 2 rows, and the passage and portal between them, found %v", walk.Marks)
+	}
+	for p, marks := range walk.Marks {
+		if marks != 2 {
+			t.Fatalf("expected every passage to be walked twice, but %+v was walked %d times", p, marks)
+		}
+	}
+
+	g.ConnectCell(g.Cell(1, 0), grid.SOUTH)
+	walk = Tremaux(g, g.Cell(0, 0), g.Cell(2, 1))
+	checkMarks(t, "reachable", walk)
+	if !walk.Solved {
+		t.Fatalf("expected the walk to reach the bottom row, found %+v", walk)
+	}
+}
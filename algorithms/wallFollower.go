@@ -0,0 +1,140 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"github.com/faiface/pixel"
+	"image/color"
+)
+
+// Walk is the route a solver took through a maze, step by step
+type Walk struct {
+	Trace  []int // every cell walked into, in order from the start, including dead ends and doubling back
+	Path   []int // the route from the start to where the walk stopped, with the dead ends and loops in the trace cut out
+	Solved bool  // whether the walk reached the end
+	// Loop is set when a wall follower gives up because it's walking around an island: it's the cells it would go around forever
+	Loop []int
+}
+
+// clockwise orders directions around a cell, so a walker knows which way is left and which way is right
+// planar mazes number their neighbors in this order too
+var clockwise = []grid.Direction{grid.NORTH, grid.NORTHEAST, grid.EAST, grid.SOUTHEAST, grid.SOUTH, grid.SOUTHWEST, grid.WEST, grid.NORTHWEST, grid.UP, grid.DOWN}
+
+// LeftHand walks from start keeping its left hand on the wall until it reaches end, or it's back where it's already been facing the same way
+// that always finds the end of a perfect maze, but it can circle an island in a maze with loops forever. Portals are walked past
+func LeftHand(g grid.Maze, start, end grid.Cell) Walk {
+	return followWall(g, start, end, true)
+}
+
+// RightHand is LeftHand with the right hand on the wall
+func RightHand(g grid.Maze, start, end grid.Cell) Walk {
+	return followWall(g, start, end, false)
+}
+
+func followWall(g grid.Maze, start, end grid.Cell, left bool) Walk {
+	walk := Walk{Trace: []int{start.Index()}}
+
+	type step struct {
+		cell int
+		dir  grid.Direction
+	}
+	seen := make(map[step]int) // when each step was first taken, by its place in the trace
+
+	cell := start
+	back := grid.Direction(-1) // the way the walker came in, which it's facing away from
+	for cell.Index() != end.Index() {
+		dirs := around(g, cell)
+		from := -1
+		for i, d := range dirs {
+			if d == back {
+				from = i
+			}
+		}
+		if from < 0 { // no way in yet; face the first way out, as if coming in from just before it
+			from = len(dirs) - 1
+			if !left {
+				from = 0
+			}
+		}
+
+		// the walker's left is the next way clockwise from the way it came in, and its right is the next way counterclockwise
+		// it only turns back the way it came once every other way is walled off
+		dir := grid.Direction(-1)
+		for i := 1; i <= len(dirs); i++ {
+			next := (from + i) % len(dirs)
+			if !left {
+				next = (from - i + len(dirs)) % len(dirs)
+			}
+			if cell.Connected(dirs[next]) {
+				dir = dirs[next]
+				break
+			}
+		}
+		if dir < 0 { // walled in
+			break
+		}
+
+		if first, ok := seen[step{cell.Index(), dir}]; ok {
+			walk.Loop = walk.Trace[first : len(walk.Trace)-1]
+			break
+		}
+		seen[step{cell.Index(), dir}] = len(walk.Trace) - 1
+
+		back = backDir(g, cell, dir)
+		cell = *cell.Neighbor(dir)
+		walk.Trace = append(walk.Trace, cell.Index())
+	}
+
+	walk.Solved = cell.Index() == end.Index()
+	walk.Path = reduce(walk.Trace)
+	return walk
+}
+
+// around returns the directions cell has neighbors in, in clockwise order
+func around(g grid.Maze, cell grid.Cell) []grid.Direction {
+	var dirs []grid.Direction
+	for _, d := range clockwise {
+		for _, available := range g.Directions() {
+			// a tunnel leaves on the same side as the passage it goes under, and only one of them can be there at a time
+			if available == d || (available.Tunnel() && available-grid.TUNNELNORTH == d) {
+				if cell.HasNeighbor(available) {
+					dirs = append(dirs, available)
+				}
+			}
+		}
+	}
+	return dirs
+}
+
+// reduce cuts the loops out of a trace, leaving the route from its first cell to its last that doesn't visit any cell twice
+func reduce(trace []int) []int {
+	var path []int
+	at := make(map[int]int) // where each cell is in the path
+	for _, idx := range trace {
+		if i, ok := at[idx]; ok {
+			for _, cut := range path[i+1:] {
+				delete(at, cut)
+			}
+			path = path[:i+1]
+			continue
+		}
+		at[idx] = len(path)
+		path = append(path, idx)
+	}
+	return path
+}
+
+// Draw draws the whole trace thinly, with the route it found over it
+func (w Walk) Draw(g grid.Maze, window pixel.Target, size pixel.Rect, thickness float64) {
+	drawPath(g, w.Trace, window, size, thickness/4, color.RGBA{
+		R: 255,
+		G: 140,
+		B: 0,
+		A: 255,
+	})
+	drawPath(g, w.Path, window, size, thickness/2, color.RGBA{
+		R: 0,
+		G: 255,
+		B: 0,
+		A: 255,
+	})
+}
@@ -0,0 +1,101 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkSteps checks that each cell in cells links to the next one
+func checkSteps(t *testing.T, name string, g grid.Maze, cells []int) {
+	t.Helper()
+	for i := 1; i < len(cells); i++ {
+		var linked bool
+		for _, next := range links(g, g.CellForIndex(cells[i-1])) {
+			linked = linked || next.Index() == cells[i]
+		}
+		if !linked {
+			t.Fatalf("%s: %v steps from %d to %d, which aren't linked", name, cells, cells[i-1], cells[i])
+		}
+	}
+}
+
+func TestWallFollowers(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	followers := map[string]func(g grid.Maze, start, end grid.Cell) Walk{
+		"left hand":  LeftHand,
+		"right hand": RightHand,
+	}
+
+	for _, test := range solverMazes(random) {
+		g := test.maze
+		d := NewDijkstra(g)
+		for i := 0; i < 10; i++ {
+			start, end := g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size()))
+			d.Init(start)
+			shortest := d.ShortestPath(end)
+			for i, j := 0, len(shortest)-1; i < j; i, j = i+1, j-1 {
+				shortest[i], shortest[j] = shortest[j], shortest[i]
+			}
+
+			for hand, follow := range followers {
+				name := test.name + " " + hand
+				walk := follow(g, start, end)
+				checkSteps(t, name, g, walk.Trace)
+				checkSteps(t, name, g, walk.Path)
+				if walk.Trace[0] != start.Index() || walk.Path[0] != start.Index() || walk.Path[len(walk.Path)-1] != walk.Trace[len(walk.Trace)-1] {
+					t.Fatalf("%s: walk from %d doesn't start there or its path doesn't end where its trace does: %+v", name, start.Index(), walk)
+				}
+
+				// a perfect maze only has one way through, and following a wall always finds it
+				if test.perfect && (!walk.Solved || walk.Loop != nil || !reflect.DeepEqual(walk.Path, shortest)) {
+					t.Fatalf("%s: expected the walk from %d to %d to follow %v, found %+v", name, start.Index(), end.Index(), shortest, walk)
+				}
+				if !walk.Solved && walk.Loop == nil {
+					t.Fatalf("%s: walk from %d to %d stopped without solving the maze or finding a loop: %+v", name, start.Index(), end.Index(), walk)
+				}
+				if walk.Solved && walk.Path[len(walk.Path)-1] != end.Index() {
+					t.Fatalf("%s: walk from %d claims to have reached %d, but stopped at %d", name, start.Index(), end.Index(), walk.Path[len(walk.Path)-1])
+				}
+			}
+		}
+	}
+}
+
+func TestWallFollowerIsland(t *testing.T) {
+	// a ring of cells around the middle of the top 4 rows, which only opens onto the ring from the north
+	// the walk starts in a dead end off the outer wall, so the walker's hand stays on the outer wall, around the ring and back through the dead end
+	//  ___ ___ ___ ___
+	// |            ___|
+	// |   |    _  |   |
+	// |   |___|_  |   |
+	// |    ___ ___    |
+	// |   |___|___|___|
+	g := grid.New(5, 4)
+	for c := 0; c < 3; c++ {
+		g.ConnectCell(g.Cell(0, c), grid.EAST)
+		g.ConnectCell(g.Cell(3, c), grid.EAST)
+	}
+	for r := 0; r < 3; r++ {
+		g.ConnectCell(g.Cell(r, 0), grid.SOUTH)
+		g.ConnectCell(g.Cell(r, 3), grid.SOUTH)
+	}
+	g.ConnectCell(g.Cell(0, 1), grid.SOUTH)
+	g.ConnectCell(g.Cell(1, 1), grid.EAST)
+	g.ConnectCell(g.Cell(1, 2), grid.SOUTH)
+	g.ConnectCell(g.Cell(4, 0), grid.NORTH)
+	start, end := g.Cell(4, 0), g.Cell(2, 2)
+
+	for hand, walk := range map[string]Walk{"left hand": LeftHand(g, start, end), "right hand": RightHand(g, start, end)} {
+		if walk.Solved || len(walk.Loop) != 14 {
+			t.Fatalf("%s: expected the walk to go around the ring and through the dead end, 14 steps, found %+v", hand, walk)
+		}
+		checkSteps(t, hand, g, append(walk.Loop, walk.Loop[0]))
+		for _, idx := range walk.Loop {
+			if r, c := idx/4, idx%4; r >= 1 && r <= 2 && c >= 1 && c <= 2 {
+				t.Fatalf("%s: expected the walk to stay out of the middle, but it went into %d", hand, idx)
+			}
+		}
+	}
+}
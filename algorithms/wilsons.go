@@ -5,10 +5,11 @@ import (
 	"math/rand"
 )
 
-func Wilsons(g grid.Grid) {
-	size := g.Rows() * g.Cols()
+func Wilsons(g grid.Maze) {
+	size := g.Size()
 	node := g.CellForIndex(rand.Intn(size))
 	visited := make([]bool, size)
+	dirs := g.Directions()
 	pathed := make([]bool, size)
 	options := make([]int, size)
 	for i := 0; i < size; i++ {
@@ -19,13 +20,21 @@ func Wilsons(g grid.Grid) {
 	path := make([]grid.Cell, 0, size/2) // on large grids, size/2 is a reasonable initial memory guess
 	pathed[node.Index()] = true
 	for {
-		dir := grid.Direction(rand.Intn(int(grid.WEST) + 1))
+		dir := dirs[rand.Intn(len(dirs))]
 		if node.HasNeighbor(dir) {
 			next := *node.Neighbor(dir)
 
 			if visited[next.Index()] { // connect the path to the visited maze
 				for i := len(path) - 1; i >= 0; i-- {
-					g.Connect(next.Row(), next.Col(), g.CellDir(next, path[i]))
+					dir := g.CellDir(next, path[i])
+					if n := next.Neighbor(dir); n == nil || n.Index() != path[i].Index() {
+						// a tunnel and a passage on this path both wanted the same wall, leave the rest for another walk
+						for _, n := range path[:i+1] {
+							pathed[n.Index()] = false
+						}
+						break
+					}
+					g.ConnectCell(next, dir)
 					next = path[i]
 					visited[next.Index()] = true
 				}
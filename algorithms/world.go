@@ -0,0 +1,143 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"math/rand"
+)
+
+// World is an endless maze made of square chunks. Each chunk is generated the first time it's needed,
+// from the world's seed and the chunk's position, so a world always comes out the same whichever order its chunks are visited in
+type World struct {
+	seed      int64
+	chunkSize int // cells along each side of a chunk
+	doors     int // most passages between two chunks
+	chunks    map[[2]int]Chunk
+}
+
+// Chunk is one square piece of a world, holding a perfect maze with doors into the chunks around it
+type Chunk struct {
+	grid.Grid
+	Row, Col int // position of the chunk in the world, in chunks
+	// Doors lists the positions along each edge (indexed by NORTH, EAST, SOUTH and WEST) with a passage into the next chunk
+	// positions are columns along the north and south edges and rows along the east and west edges
+	Doors [grid.WEST + 1][]int
+}
+
+// NewWorld returns a world of chunkSize x chunkSize chunks joined to each other by 1 to doors passages along each edge
+// chunks are at least 1 cell across, and there can't be more doors along an edge than there are cells
+func NewWorld(seed int64, chunkSize, doors int) *World {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	if doors > chunkSize {
+		doors = chunkSize
+	}
+	if doors < 1 {
+		doors = 1
+	}
+	return &World{
+		seed:      seed,
+		chunkSize: chunkSize,
+		doors:     doors,
+		chunks:    make(map[[2]int]Chunk),
+	}
+}
+
+func (w *World) ChunkSize() int {
+	return w.chunkSize
+}
+
+// Chunk returns the chunk at row, col (in chunks), generating it if it hasn't been yet
+func (w *World) Chunk(row, col int) Chunk {
+	if chunk, ok := w.chunks[[2]int{row, col}]; ok {
+		return chunk
+	}
+
+	chunk := Chunk{
+		Grid: grid.New(w.chunkSize, w.chunkSize),
+		Row:  row,
+		Col:  col,
+	}
+	kruskals(chunk.Grid, w.random(row, col, 0).Shuffle)
+
+	// each edge is shared with the next chunk over, so its doors come from whichever chunk is north or west of it
+	chunk.Doors[grid.NORTH] = w.seam(row-1, col, grid.SOUTH)
+	chunk.Doors[grid.EAST] = w.seam(row, col, grid.EAST)
+	chunk.Doors[grid.SOUTH] = w.seam(row, col, grid.SOUTH)
+	chunk.Doors[grid.WEST] = w.seam(row, col-1, grid.EAST)
+
+	w.chunks[[2]int{row, col}] = chunk
+	return chunk
+}
+
+// Forget drops a generated chunk to save memory. It comes back the same the next time it's needed
+func (w *World) Forget(row, col int) {
+	delete(w.chunks, [2]int{row, col})
+}
+
+// Connected reports whether the cell at row, col (in cells, anywhere in the world) is linked to its neighbor in dir
+func (w *World) Connected(row, col int, dir grid.Direction) bool {
+	chunkRow, r := split(row, w.chunkSize)
+	chunkCol, c := split(col, w.chunkSize)
+	chunk := w.Chunk(chunkRow, chunkCol)
+
+	// passages off the edge of a chunk go through its doors
+	var along int
+	switch {
+	case dir == grid.NORTH && r == 0, dir == grid.SOUTH && r == w.chunkSize-1:
+		along = c
+	case dir == grid.WEST && c == 0, dir == grid.EAST && c == w.chunkSize-1:
+		along = r
+	default:
+		return chunk.Cell(r, c).Connected(dir)
+	}
+	for _, door := range chunk.Doors[dir] {
+		if door == along {
+			return true
+		}
+	}
+	return false
+}
+
+// Window copies the rows x cols cells of the world with their top left corner at row, col (in cells) into a grid,
+// generating whatever chunks it covers, so the usual solvers and renderers can work on it
+func (w *World) Window(row, col, rows, cols int) grid.Grid {
+	g := grid.New(rows, cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if c+1 < cols && w.Connected(row+r, col+c, grid.EAST) {
+				g.Connect(r, c, grid.EAST)
+			}
+			if r+1 < rows && w.Connected(row+r, col+c, grid.SOUTH) {
+				g.Connect(r, c, grid.SOUTH)
+			}
+		}
+	}
+	return g
+}
+
+// seam returns the doors through the east or south edge of the chunk at row, col
+func (w *World) seam(row, col int, dir grid.Direction) []int {
+	random := w.random(row, col, 1+int64(dir))
+	return random.Perm(w.chunkSize)[:1+random.Intn(w.doors)]
+}
+
+// random returns a random source that only depends on the world's seed, a chunk and what it's used for
+func (w *World) random(row, col int, use int64) *rand.Rand {
+	// splitmix64, so neighboring chunks get unrelated sources
+	x := uint64(w.seed) ^ uint64(row)*0x9E3779B97F4A7C15 ^ uint64(col)*0xC2B2AE3D27D4EB4F ^ uint64(use)*0x165667B19E3779F9
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	x ^= x >> 31
+
+	return rand.New(rand.NewSource(int64(x)))
+}
+
+// split divides a position in cells into the chunk it's in and the position inside that chunk, rounding down for negative positions
+func split(pos, size int) (int, int) {
+	chunk := pos / size
+	if pos%size < 0 {
+		chunk--
+	}
+	return chunk, pos - chunk*size
+}
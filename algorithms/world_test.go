@@ -0,0 +1,58 @@
+package algorithms
+
+import (
+	"github.com/bionoren/mazes/grid"
+	"reflect"
+	"testing"
+)
+
+func TestWorldDeterministic(t *testing.T) {
+	a, b := NewWorld(42, 8, 3), NewWorld(42, 8, 3)
+
+	// visit the chunks in opposite orders
+	var positions [][2]int
+	for row := -2; row <= 2; row++ {
+		for col := -2; col <= 2; col++ {
+			positions = append(positions, [2]int{row, col})
+		}
+	}
+	for _, p := range positions {
+		a.Chunk(p[0], p[1])
+	}
+	for i := len(positions) - 1; i >= 0; i-- {
+		b.Chunk(positions[i][0], positions[i][1])
+	}
+
+	for _, p := range positions {
+		chunk := a.Chunk(p[0], p[1])
+		if other := b.Chunk(p[0], p[1]); !chunk.Equal(other.Grid) || !reflect.DeepEqual(chunk.Doors, other.Doors) {
+			t.Fatalf("chunk %v came out differently in the same world:\n%s\n%s", p, chunk, other)
+		}
+
+		a.Forget(p[0], p[1])
+		if again := a.Chunk(p[0], p[1]); !again.Equal(chunk.Grid) || !reflect.DeepEqual(again.Doors, chunk.Doors) {
+			t.Fatalf("chunk %v came out differently after it was forgotten:\n%s\n%s", p, chunk, again)
+		}
+	}
+
+	if c := NewWorld(43, 8, 3).Chunk(0, 0); c.Equal(a.Chunk(0, 0).Grid) {
+		t.Fatal("expected a different seed to give a different chunk")
+	}
+}
+
+func TestWorldWindow(t *testing.T) {
+	w := NewWorld(7, 5, 2)
+	if v := grid.Validate(w.Window(-5, -10, 15, 20)); !v.Connected() {
+		t.Fatalf("expected a window lined up with the chunks to be connected, found %+v", v)
+	}
+}
+
+func TestWorldSettings(t *testing.T) {
+	for _, settings := range [][2]int{{0, 0}, {-3, 2}, {2, 5}, {4, -1}} {
+		w := NewWorld(1, settings[0], settings[1])
+		chunk := w.Chunk(0, 0)
+		if chunk.Rows() < 1 || len(chunk.Doors[grid.EAST]) < 1 || len(chunk.Doors[grid.EAST]) > chunk.Rows() {
+			t.Fatalf("chunk size %d with %d doors: expected at least one cell and one door per edge, found\n%s%v", settings[0], settings[1], chunk, chunk.Doors)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package grid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// binary format:
+//
+//	"MZ"                  magic
+//	version               1 byte
+//	flags                 1 byte, how the edges wrap around (see the wrap* constants)
+//	rows, cols            uvarints
+//	links                 2 bits per cell in index order, east then south, packed starting from the low bit of each byte
+const (
+	binaryMagic   = "MZ"
+	binaryVersion = 1
+)
+
+// MarshalBinary encodes the grid in about 2 bits per cell
+// only whole grids with links in the four compass directions (including wrapped grids) can be encoded,
+// and only without portals, layers or one-way passages. MarshalJSON keeps those
+func (g Grid) MarshalBinary() ([]byte, error) {
+	if len(g.directions) != len(orthogonal) {
+		return nil, errors.New("grid: only square grids can be marshaled")
+	}
+	if !g.standalone() {
+		return nil, errors.New("grid: a level or face of a bigger maze can't be marshaled on its own")
+	}
+	if len(g.store.portals) > 0 {
+		return nil, errors.New("grid: portals can't be stored in the binary format")
+	}
+	if len(g.store.layers) > 0 {
+		return nil, errors.New("grid: layers can't be stored in the binary format")
+	}
+	for i := 0; i < g.Size(); i++ {
+		if cell := g.CellForIndex(i); cell.OneWay(EAST) || cell.OneWay(SOUTH) {
+			return nil, errors.New("grid: one-way passages can't be stored in the binary format")
+		}
+	}
+
+	rows, cols := g.Rows(), g.Cols()
+	data := make([]byte, 0, len(binaryMagic)+2+2*binary.MaxVarintLen64+(2*rows*cols+7)/8)
+	data = append(data, binaryMagic...)
+	data = append(data, binaryVersion, g.wrapFlags())
+
+	var buf [binary.MaxVarintLen64]byte
+	data = append(data, buf[:binary.PutUvarint(buf[:], uint64(rows))]...)
+	data = append(data, buf[:binary.PutUvarint(buf[:], uint64(cols))]...)
+
+	links := make([]byte, (2*rows*cols+7)/8)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			bit := 2 * (r*cols + c)
+			if g.Cell(r, c).Connected(EAST) {
+				links[bit/8] |= 1 << (bit % 8)
+			}
+			if g.Cell(r, c).Connected(SOUTH) {
+				links[(bit+1)/8] |= 1 << ((bit + 1) % 8)
+			}
+		}
+	}
+
+	return append(data, links...), nil
+}
+
+// standalone reports whether the grid is a whole maze, not one level of a 3D grid or one face of a cube
+func (g Grid) standalone() bool {
+	_, ok := g.store.shape.(*flat)
+	return ok && g.offset == 0
+}
+
+// wrapFlags returns how the grid's edges wrap around. Levels and faces of bigger mazes don't wrap; their edges lead to other levels or faces
+func (g Grid) wrapFlags() byte {
+	if f, ok := g.store.shape.(*flat); ok {
+		return f.wrap
+	}
+	return 0
+}
+
+// UnmarshalBinary replaces the grid with one decoded from data, which must have been made by MarshalBinary
+func (g *Grid) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+2 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errors.New("grid: not a binary maze")
+	}
+	data = data[len(binaryMagic):]
+	if data[0] != binaryVersion {
+		return fmt.Errorf("grid: unsupported binary maze version %d", data[0])
+	}
+	flags := data[1]
+	if flags&^(wrapEastWest|twistEastWest|wrapNorthSouth|twistNorthSouth) != 0 {
+		return fmt.Errorf("grid: unknown flags %#x", flags)
+	}
+	data = data[2:]
+
+	rows, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("grid: bad row count")
+	}
+	data = data[n:]
+	cols, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("grid: bad column count")
+	}
+	data = data[n:]
+	if rows == 0 || cols == 0 || rows > uint64(len(data))*8 || cols > uint64(len(data))*8 {
+		return fmt.Errorf("grid: bad dimensions %dx%d", rows, cols)
+	}
+	if expected := (2*rows*cols + 7) / 8; uint64(len(data)) != expected {
+		return fmt.Errorf("grid: expected %d bytes of links for a %dx%d grid, found %d", expected, rows, cols, len(data))
+	}
+
+	grid := New(int(rows), int(cols))
+	if flags&wrapEastWest != 0 {
+		grid.wrapEastWest(flags&twistEastWest != 0)
+	}
+	if flags&wrapNorthSouth != 0 {
+		grid.wrapNorthSouth(flags&twistNorthSouth != 0)
+	}
+
+	for r := 0; r < grid.Rows(); r++ {
+		for c := 0; c < grid.Cols(); c++ {
+			bit := 2 * (r*grid.Cols() + c)
+			for i, dir := range [2]Direction{EAST, SOUTH} {
+				if data[(bit+i)/8]&(1<<((bit+i)%8)) == 0 {
+					continue
+				}
+				if grid.Cell(r, c).neighbor(dir) < 0 {
+					return fmt.Errorf("grid: cell %d,%d is linked off the edge of the grid", r, c)
+				}
+				grid.Connect(r, c, dir)
+			}
+		}
+	}
+
+	*g = grid
+	return nil
+}
@@ -0,0 +1,50 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, topology := range topologies {
+		for _, size := range [][2]int{{1, 1}, {1, 4}, {2, 2}, {2, 5}, {3, 2}, {6, 7}} {
+			for i := 0; i < 20; i++ {
+				g := topology.make(size[0], size[1])
+				randomLinks(g, random, false)
+
+				data, err := g.MarshalBinary()
+				if err != nil {
+					t.Fatalf("%s %v: %v", topology.name, size, err)
+				}
+				var decoded Grid
+				if err := decoded.UnmarshalBinary(data); err != nil {
+					t.Fatalf("%s %v: %v", topology.name, size, err)
+				}
+				if !decoded.Equal(g) {
+					t.Fatalf("%s %v: decoded\n%s\nfrom\n%s", topology.name, size, decoded, g)
+				}
+			}
+		}
+	}
+}
+
+func TestBinaryRefuses(t *testing.T) {
+	oneWay := New(2, 2)
+	oneWay.ConnectOneWay(0, 0, EAST)
+	portal := New(2, 2)
+	AddPortal(portal.Cell(0, 0), portal.Cell(1, 1))
+	layer := New(2, 2)
+	LayerOf(layer, "items").Set(layer.Cell(0, 0), "key")
+
+	for name, g := range map[string]Grid{
+		"upsilon": NewUpsilon(2, 2).Grid,
+		"one-way": oneWay,
+		"portal":  portal,
+		"layer":   layer,
+	} {
+		if _, err := g.MarshalBinary(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -7,8 +7,21 @@ const (
 	EAST
 	SOUTH
 	WEST
+	NORTHEAST
+	SOUTHEAST
+	SOUTHWEST
+	NORTHWEST
+	TUNNELNORTH // under the cell to the north, to the cell beyond it
+	TUNNELEAST
+	TUNNELSOUTH
+	TUNNELWEST
+	UP
+	DOWN
 )
 
+// orthogonal are the directions available in a plain square grid
+var orthogonal = []Direction{NORTH, EAST, SOUTH, WEST}
+
 func (d Direction) Reverse() Direction {
 	switch d {
 	case NORTH:
@@ -19,52 +32,191 @@ func (d Direction) Reverse() Direction {
 		return NORTH
 	case WEST:
 		return EAST
+	case NORTHEAST:
+		return SOUTHWEST
+	case SOUTHEAST:
+		return NORTHWEST
+	case SOUTHWEST:
+		return NORTHEAST
+	case NORTHWEST:
+		return SOUTHEAST
+	case TUNNELNORTH:
+		return TUNNELSOUTH
+	case TUNNELEAST:
+		return TUNNELWEST
+	case TUNNELSOUTH:
+		return TUNNELNORTH
+	case TUNNELWEST:
+		return TUNNELEAST
+	case UP:
+		return DOWN
+	case DOWN:
+		return UP
 	}
 
 	return NORTH
 }
 
+// Tunnel reports whether d passes under a neighboring cell
+func (d Direction) Tunnel() bool {
+	return d >= TUNNELNORTH && d <= TUNNELWEST
+}
+
+// surface returns the direction of the cell a tunnel passes under
+func (d Direction) surface() Direction {
+	return d - TUNNELNORTH
+}
+
+// tunnel returns the tunnel direction passing under the neighbor in direction d
+func (d Direction) tunnel() Direction {
+	return d + TUNNELNORTH
+}
+
+// perpendicular returns the orthogonal direction clockwise from d
+func (d Direction) perpendicular() Direction {
+	return (d + 1) % (WEST + 1)
+}
+
+// Cell is a handle to one cell of a maze. Copies all refer to the same cell, so they see links made after they were copied
 type Cell struct {
-	index     int
-	row, col  int
-	neighbors [WEST + 1]*Cell
-	openings  [WEST + 1]bool
+	store *store
+	index int
 }
 
 func (c Cell) Index() int {
 	return c.index
 }
 
+// Level returns the floor of a 3D grid or the face of a cube this cell is on (0 for flat grids)
+func (c Cell) Level() int {
+	level, _, _ := c.store.shape.position(c.index)
+	return level
+}
+
 func (c Cell) Row() int {
-	return c.row
+	_, row, _ := c.store.shape.position(c.index)
+	return row
 }
 
 func (c Cell) Col() int {
-	return c.col
+	_, _, col := c.store.shape.position(c.index)
+	return col
 }
 
-func (c *Cell) addNeighbor(neighbor *Cell, dir Direction) {
-	c.neighbors[dir] = neighbor
+// neighbor returns the index of the cell next to this one in dir, or -1, whether or not a tunnel is in the way
+func (c Cell) neighbor(dir Direction) int {
+	return c.store.shape.neighbor(c.index, dir)
 }
 
 func (c Cell) Neighbor(dir Direction) *Cell {
-	return c.neighbors[dir]
+	n := c.neighbor(dir)
+	if n < 0 {
+		return nil
+	}
+	if dir.Tunnel() {
+		if !c.Connected(dir) && !c.canTunnel(dir) {
+			return nil
+		}
+	} else if dir <= WEST && (c.Connected(dir.tunnel()) || c.under(dir) || c.store.cell(n).under(dir)) {
+		return nil // that side is taken by a tunnel
+	}
+
+	cell := c.store.cell(n)
+	return &cell
 }
 
 func (c Cell) HasNeighbor(dir Direction) bool {
-	return c.neighbors[dir] != nil
+	return c.Neighbor(dir) != nil
+}
+
+// under reports whether a tunnel runs under this cell parallel to dir
+func (c Cell) under(dir Direction) bool {
+	if n := c.neighbor(dir); n >= 0 && c.store.linked(n, dir.Reverse().tunnel()) {
+		return true
+	}
+	if n := c.neighbor(dir.Reverse()); n >= 0 && c.store.linked(n, dir.tunnel()) {
+		return true
+	}
+	return false
 }
 
-func (c *Cell) connect(dir Direction) {
-	c.openings[dir] = true
-	c.neighbors[dir].openings[dir.Reverse()] = true
+// Under reports whether a tunnel runs under this cell
+func (c Cell) Under() bool {
+	return c.under(NORTH) || c.under(EAST)
 }
 
-func (c *Cell) disconnect(dir Direction) {
-	c.openings[dir] = false
-	c.neighbors[dir].openings[dir.Reverse()] = false
+// canTunnel reports whether the cell being tunneled under is currently a straight corridor perpendicular to the tunnel
+func (c Cell) canTunnel(dir Direction) bool {
+	o, n := c.neighbor(dir.surface()), c.neighbor(dir)
+	if o < 0 || n < 0 {
+		return false
+	}
+	over := c.store.cell(o)
+	side := dir.surface().perpendicular()
+	for d := NORTH; d <= DOWN; d++ {
+		if over.Connected(d) != (d == side || d == side.Reverse()) {
+			return false
+		}
+	}
+	return !over.under(dir.surface()) && !c.under(dir.surface()) && !c.store.cell(n).under(dir.surface())
 }
 
+func (c Cell) connect(dir Direction) {
+	c.setLinked(dir, true)
+}
+
+// connectOneWay opens a passage that can only be taken from this cell in dir, not back the other way
+func (c Cell) connectOneWay(dir Direction) {
+	n := c.store.cell(c.neighbor(dir))
+	c.store.setLinked(c.index, dir, true)
+	c.store.setLinked(n.index, n.back(c, dir), false)
+}
+
+func (c Cell) disconnect(dir Direction) {
+	c.setLinked(dir, false)
+}
+
+func (c Cell) setLinked(dir Direction, linked bool) {
+	n := c.store.cell(c.neighbor(dir))
+	c.store.setLinked(c.index, dir, linked)
+	c.store.setLinked(n.index, n.back(c, dir), linked)
+}
+
+// back returns the direction from c back to from, which reached c by going dir
+// that's usually the reverse of dir, but not across the edges of a cube or between rings of a sphere
+func (c Cell) back(from Cell, dir Direction) Direction {
+	if c.neighbor(dir.Reverse()) == from.index {
+		return dir.Reverse()
+	}
+	for d := NORTH; d <= DOWN; d++ {
+		if c.neighbor(d) == from.index {
+			return d
+		}
+	}
+	return dir.Reverse()
+}
+
+// Connected reports whether the passage in dir can be taken from this cell
 func (c Cell) Connected(dir Direction) bool {
-	return c.openings[dir]
+	return c.store.linked(c.index, dir)
+}
+
+// linkedBack reports whether the passage from the neighbor in dir back to this cell can be taken
+func (c Cell) linkedBack(dir Direction) bool {
+	idx := c.neighbor(dir)
+	if idx < 0 {
+		return false
+	}
+	n := c.store.cell(idx)
+	return n.Connected(n.back(c, dir))
+}
+
+// open reports whether there's a passage in dir, whichever way it can be taken
+func (c Cell) open(dir Direction) bool {
+	return c.Connected(dir) || c.linkedBack(dir)
+}
+
+// OneWay reports whether the passage in dir can only be taken in one direction (from this cell, if Connected)
+func (c Cell) OneWay(dir Direction) bool {
+	return c.Connected(dir) != c.linkedBack(dir)
 }
@@ -0,0 +1,118 @@
+package grid
+
+// clone returns a copy of the store that can be changed without changing s
+func (s *store) clone() *store {
+	c := *s
+	if f, ok := s.shape.(*flat); ok {
+		shape := *f
+		c.shape = &shape
+	}
+	c.links = append([]uint64(nil), s.links...)
+	if s.portals != nil {
+		c.portals = make(map[int][]int, len(s.portals))
+		for idx, ends := range s.portals {
+			c.portals[idx] = append([]int(nil), ends...)
+		}
+	}
+
+	if s.layers != nil {
+		c.layers = make(map[string]map[int]interface{}, len(s.layers))
+		for name, values := range s.layers {
+			c.layers[name] = make(map[int]interface{}, len(values))
+			for idx, v := range values {
+				c.layers[name][idx] = v
+			}
+		}
+	}
+
+	return &c
+}
+
+// Clone returns a copy of the grid that can be connected and disconnected without changing g
+// layer values are copied too, but values that refer to other data (like pointers or slices) still share it
+// a level of a 3D grid or a face of a cube is cloned along with the rest of its maze
+func (g Grid) Clone() Grid {
+	g.store = g.store.clone()
+	return g
+}
+
+func (u Upsilon) Clone() Upsilon {
+	return Upsilon{u.Grid.Clone()}
+}
+
+func (w Weave) Clone() Weave {
+	return Weave{w.Grid.Clone()}
+}
+
+// Equal reports whether the grids are the same shape and have the same links and portals
+func (g Grid) Equal(other Grid) bool {
+	if g.Rows() != other.Rows() || g.Cols() != other.Cols() || !sameDirections(g.directions, other.directions) || g.wrapFlags() != other.wrapFlags() {
+		return false
+	}
+
+	for i := 0; i < g.Size(); i++ {
+		a, b := g.CellForIndex(i), other.CellForIndex(i)
+		for _, d := range g.directions {
+			if a.Connected(d) != b.Connected(d) {
+				return false
+			}
+		}
+	}
+
+	pairs, otherPairs := g.store.portalPairs(), other.store.portalPairs()
+	if len(pairs) != len(otherPairs) {
+		return false
+	}
+	for i := range pairs {
+		if pairs[i] != otherPairs[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Diff lists the links and portals other has that g doesn't (added) and the ones g has that other doesn't (removed)
+// a passage that changed which way it can be taken is in both. Both grids must be the same shape
+func (g Grid) Diff(other Grid) (added, removed []Link) {
+	for i := 0; i < g.Size(); i++ {
+		a, b := g.CellForIndex(i), other.CellForIndex(i)
+		for _, d := range g.directions {
+			// each passage is checked from the cell with the lower index
+			if a.neighbor(d) < a.index || (a.Connected(d) == b.Connected(d) && a.linkedBack(d) == b.linkedBack(d)) {
+				continue
+			}
+
+			if a.open(d) {
+				removed = append(removed, passage(a, d))
+			}
+			if b.open(d) {
+				added = append(added, passage(b, d))
+			}
+		}
+	}
+
+	portals := make(map[[2]int]bool)
+	for _, pair := range g.store.portalPairs() {
+		portals[pair] = true
+	}
+	for _, pair := range other.store.portalPairs() {
+		if portals[pair] {
+			delete(portals, pair)
+		} else {
+			added = append(added, g.portalLink(pair))
+		}
+	}
+	for _, pair := range g.store.portalPairs() {
+		if portals[pair] {
+			removed = append(removed, g.portalLink(pair))
+		}
+	}
+
+	return added, removed
+}
+
+func (g Grid) portalLink(pair [2]int) Link {
+	a, b := g.store.cell(pair[0]), g.store.cell(pair[1])
+	return Link{From: Position{a.Row(), a.Col()}, To: Position{b.Row(), b.Col()}}
+}
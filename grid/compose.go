@@ -0,0 +1,163 @@
+package grid
+
+import (
+	"fmt"
+)
+
+// SubGrid returns a new grid holding a copy of the rows x cols cells with their top left corner at row, col
+// links and portals between cells in the region are copied, along with the cells' layer values. Links leading out of it, including around wrapping edges, are not
+// in a grid with diagonals, row+col has to be even so the octagons stay octagons. It panics if the region isn't inside g
+func (g Grid) SubGrid(row, col, rows, cols int) Grid {
+	g.checkRegion(row, col, rows, cols)
+	sub := g.blank(rows, cols)
+	sub.copyRegion(0, 0, g, row, col, rows, cols)
+
+	return sub
+}
+
+// Crop returns a copy of the grid with the given number of rows and columns cut off each side
+func (g Grid) Crop(top, left, bottom, right int) Grid {
+	return g.SubGrid(top, left, g.Rows()-top-bottom, g.Cols()-left-right)
+}
+
+// Paste replaces the cells of g under piece, placed with its top left corner at row, col, with piece's cells and their layer values
+// the edges of the pasted region are walled off, so it's joined to the rest of g with Connect
+// piece must fit inside g and have the same kind of cells, and if it has diagonals row+col has to be even. Paste panics if it doesn't
+func (g Grid) Paste(piece Grid, row, col int) {
+	if !sameDirections(g.directions, piece.directions) {
+		panic("grid: only a grid with the same kind of cells can be pasted in")
+	}
+	g.checkRegion(row, col, piece.Rows(), piece.Cols())
+	g.copyRegion(row, col, piece, 0, 0, piece.Rows(), piece.Cols())
+}
+
+// Stitch returns a new grid with b on the side of a given by side (NORTH, EAST, SOUTH or WEST), lined up along their top or left edges
+// the pieces are joined by a passage at each of the seam positions, which are rows for EAST and WEST and columns for NORTH and SOUTH
+// if one piece is shorter along the seam, the cells beside it are left unlinked
+// the pieces must have the same kind of cells, and pieces with diagonals can only be stitched to the east or south of a piece with an even number of columns or rows
+// it panics if they don't, or if a seam position isn't beside both pieces
+func Stitch(a, b Grid, side Direction, seam ...int) Grid {
+	if !sameDirections(a.directions, b.directions) {
+		panic("grid: only grids with the same kind of cells can be stitched together")
+	}
+	switch side {
+	case NORTH, WEST:
+		return Stitch(b, a, side.Reverse(), seam...)
+	case EAST:
+		g := a.blank(max(a.Rows(), b.Rows()), a.Cols()+b.Cols())
+		g.Paste(a, 0, 0)
+		g.Paste(b, 0, a.Cols())
+		for _, r := range seam {
+			if r < 0 || r >= a.Rows() || r >= b.Rows() {
+				panic(fmt.Sprintf("grid: row %d of the seam isn't beside both pieces", r))
+			}
+			g.Connect(r, a.Cols()-1, EAST)
+		}
+		return g
+	case SOUTH:
+		g := a.blank(a.Rows()+b.Rows(), max(a.Cols(), b.Cols()))
+		g.Paste(a, 0, 0)
+		g.Paste(b, a.Rows(), 0)
+		for _, c := range seam {
+			if c < 0 || c >= a.Cols() || c >= b.Cols() {
+				panic(fmt.Sprintf("grid: column %d of the seam isn't beside both pieces", c))
+			}
+			g.Connect(a.Rows()-1, c, SOUTH)
+		}
+		return g
+	}
+	panic("grid: pieces can only be stitched together to the north, east, south or west")
+}
+
+// blank returns an unlinked, unwrapped grid with the same kinds of cells as g
+func (g Grid) blank(rows, cols int) Grid {
+	return newFlat(&flat{
+		rows:      rows,
+		cols:      cols,
+		diagonals: g.store.slots[NORTHEAST] >= 0,
+		tunnels:   g.store.slots[TUNNELNORTH] >= 0,
+	}, g.directions)
+}
+
+// copyRegion copies the links of the rows x cols cells of src at srcRow, srcCol over the cells of g at row, col
+// links between copied cells are only kept if they join the same cells in both grids. Every other link out of the region is removed
+// it panics rather than move a grid with diagonals by an odd number of rows and columns, which would swap its octagons and squares and lose every diagonal link
+func (g Grid) copyRegion(row, col int, src Grid, srcRow, srcCol, rows, cols int) {
+	if g.store.slots[NORTHEAST] >= 0 && (row+col+srcRow+srcCol)%2 != 0 {
+		panic("grid: a grid with diagonals can only be moved by an even number of rows and columns")
+	}
+
+	// inside returns the position in the region of the cell at store index idx of grid, if it's in the region
+	inside := func(grid Grid, top, left, idx int) (int, int, bool) {
+		idx -= grid.offset
+		if idx < 0 || idx >= grid.Size() {
+			return 0, 0, false
+		}
+		r, c := idx/grid.cols-top, idx%grid.cols-left
+		return r, c, r >= 0 && r < rows && c >= 0 && c < cols
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			to, from := g.Cell(row+r, col+c), src.Cell(srcRow+r, srcCol+c)
+			for _, d := range g.directions {
+				n := to.neighbor(d)
+				if n < 0 {
+					continue
+				}
+				nr, nc, ok := inside(g, row, col, n)
+				if ok && from.neighbor(d) >= 0 && from.neighbor(d) == src.Cell(srcRow+nr, srcCol+nc).index {
+					// each cell copies its own side, which keeps one-way passages one way
+					g.store.setLinked(to.index, d, from.Connected(d))
+				} else {
+					to.disconnect(d)
+				}
+			}
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			to := g.Cell(row+r, col+c)
+			for _, p := range to.Portals() {
+				g.store.removePortal(to.index, p.index)
+			}
+		}
+	}
+	for _, pair := range src.store.portalPairs() {
+		ar, ac, aok := inside(src, srcRow, srcCol, pair[0])
+		br, bc, bok := inside(src, srcRow, srcCol, pair[1])
+		if aok && bok {
+			g.store.addPortal(g.Cell(row+ar, col+ac).index, g.Cell(row+br, col+bc).index)
+		}
+	}
+
+	for _, name := range g.store.layerNames() {
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				g.store.setValue(name, g.Cell(row+r, col+c).index, nil)
+			}
+		}
+	}
+	for name, values := range src.store.layers {
+		for idx, v := range values {
+			if r, c, ok := inside(src, srcRow, srcCol, idx); ok {
+				g.store.setValue(name, g.Cell(row+r, col+c).index, v)
+			}
+		}
+	}
+}
+
+// checkRegion panics unless the rows x cols cells with their top left corner at row, col are all in the grid
+func (g Grid) checkRegion(row, col, rows, cols int) {
+	if row < 0 || col < 0 || rows < 0 || cols < 0 || row+rows > g.Rows() || col+cols > g.Cols() {
+		panic(fmt.Sprintf("grid: the %dx%d region at %d, %d isn't inside the %dx%d grid", rows, cols, row, col, g.Rows(), g.Cols()))
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,116 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomPiece returns a rows x cols grid like kind with random links, a portal and a layer value
+func randomPiece(kind func(rows, cols int) Grid, rows, cols int, random *rand.Rand) Grid {
+	g := kind(rows, cols)
+	randomLinks(g, random, true)
+	for i := 0; i < g.Size(); i++ {
+		if cell := g.CellForIndex(i); cell.neighbor(SOUTHEAST) >= 0 && random.Intn(2) == 0 {
+			cell.connect(SOUTHEAST)
+		}
+	}
+	AddPortal(g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size())))
+	LayerOf(g, "items").Set(g.CellForIndex(random.Intn(g.Size())), random.Intn(10))
+	return g
+}
+
+func upsilon(rows, cols int) Grid {
+	return NewUpsilon(rows, cols).Grid
+}
+
+func sameLayer(a, b Grid, name string) bool {
+	ac, bc := LayerOf(a, name).Cells(), LayerOf(b, name).Cells()
+	if len(ac) != len(bc) {
+		return false
+	}
+	for i := range ac {
+		if ac[i].index != bc[i].index || ac[i].Value(name) != bc[i].Value(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPasteSubGrid(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for name, kind := range map[string]func(rows, cols int) Grid{"grid": New, "upsilon": upsilon} {
+		for i := 0; i < 50; i++ {
+			piece := randomPiece(kind, 1+random.Intn(5), 1+random.Intn(5), random)
+			g := randomPiece(kind, piece.Rows()+4, piece.Cols()+4, random)
+			row, col := random.Intn(5), random.Intn(5)
+			if name == "upsilon" && (row+col)%2 != 0 {
+				if col > 0 {
+					col--
+				} else {
+					col++
+				}
+			}
+
+			g.Paste(piece, row, col)
+			back := g.SubGrid(row, col, piece.Rows(), piece.Cols())
+			if !back.Equal(piece) || !sameLayer(back, piece, "items") {
+				t.Fatalf("%s: pasted\n%s\nat %d, %d and got back\n%s", name, piece, row, col, back)
+			}
+			if crop := g.Crop(row, col, g.Rows()-row-piece.Rows(), g.Cols()-col-piece.Cols()); !crop.Equal(back) {
+				t.Fatalf("%s: expected cropping to give the same grid as SubGrid, found\n%s", name, crop)
+			}
+		}
+	}
+}
+
+func TestStitch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		a := randomPiece(New, 1+random.Intn(5), 1+random.Intn(5), random)
+		b := randomPiece(New, 1+random.Intn(5), 1+random.Intn(5), random)
+
+		east := Stitch(a, b, EAST, 0)
+		if !east.SubGrid(0, 0, a.Rows(), a.Cols()).Equal(a) || !east.SubGrid(0, a.Cols(), b.Rows(), b.Cols()).Equal(b) {
+			t.Fatalf("expected to find the pieces in\n%s", east)
+		}
+		if !east.Cell(0, a.Cols()-1).Connected(EAST) {
+			t.Fatalf("expected a passage along the seam of\n%s", east)
+		}
+		if west := Stitch(b, a, WEST, 0); !west.Equal(east) {
+			t.Fatalf("expected stitching b to the west of a to be the same as a to the east of b, found\n%s\nand\n%s", west, east)
+		}
+
+		south := Stitch(a, b, SOUTH, 0)
+		if !south.SubGrid(0, 0, a.Rows(), a.Cols()).Equal(a) || !south.SubGrid(a.Rows(), 0, b.Rows(), b.Cols()).Equal(b) {
+			t.Fatalf("expected to find the pieces in\n%s", south)
+		}
+	}
+}
+
+func TestComposeRefuses(t *testing.T) {
+	g := New(3, 3)
+	u := NewUpsilon(4, 4)
+	for name, compose := range map[string]func(){
+		"region past the edge":        func() { g.SubGrid(1, 2, 2, 2) },
+		"region before the edge":      func() { g.SubGrid(-1, 0, 2, 2) },
+		"negative size":               func() { g.SubGrid(0, 0, -1, 2) },
+		"crop too much":               func() { g.Crop(2, 0, 2, 0) },
+		"piece too big":               func() { New(2, 2).Paste(New(2, 3), 0, 0) },
+		"piece hanging off":           func() { g.Paste(New(2, 2), 2, 0) },
+		"different cells":             func() { u.Paste(New(2, 2), 0, 0) },
+		"odd offset":                  func() { u.Paste(NewUpsilon(2, 2).Grid, 0, 1) },
+		"stitch different cells":      func() { Stitch(g, u.Grid, EAST) },
+		"seam past the shorter piece": func() { Stitch(g, New(2, 2), EAST, 2) },
+		"negative seam":               func() { Stitch(g, g, SOUTH, -1) },
+		"stitch up":                   func() { Stitch(g, g, UP) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			compose()
+		}()
+	}
+}
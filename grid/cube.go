@@ -0,0 +1,247 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"strings"
+)
+
+// Cube covers the six faces of a cube with size x size grids
+// faces are numbered as they're laid out when the cube is unfolded:
+//
+//	  0
+//	1 2 3 4
+//	  5
+//
+// so 2 is the front, 0 the top and 5 the bottom. A cell's face is its Level()
+type Cube struct {
+	faces []Grid
+}
+
+// netPositions is the row and column of each face in the unfolded cube
+var netPositions = [6][2]int{{0, 1}, {1, 0}, {1, 1}, {1, 2}, {1, 3}, {2, 1}}
+
+func NewCube(size int) Cube {
+	g := Cube{
+		faces: make([]Grid, 6),
+	}
+	st := newStore(cube{size}, 6*size*size, orthogonal)
+
+	for f := range g.faces {
+		g.faces[f] = Grid{
+			store:      st,
+			offset:     f * size * size,
+			rows:       size,
+			cols:       size,
+			directions: orthogonal,
+		}
+	}
+
+	return g
+}
+
+// cube is the shape of a cube with size x size faces
+type cube struct {
+	size int
+}
+
+func (c cube) position(idx int) (int, int, int) {
+	faceSize := c.size * c.size
+	return idx / faceSize, idx % faceSize / c.size, idx % c.size
+}
+
+func (c cube) neighbor(idx int, dir Direction) int {
+	if dir > WEST {
+		return -1
+	}
+	f, r, col := c.position(idx)
+	f, r, col = cubeNeighbor(c.size, f, r, col, dir)
+	return (f*c.size+r)*c.size + col
+}
+
+// cubeNeighbor returns the face, row and col of the cell next to face, row, col in dir
+func cubeNeighbor(size, face, row, col int, dir Direction) (int, int, int) {
+	r, c := row, col
+	switch dir {
+	case NORTH:
+		r--
+	case SOUTH:
+		r++
+	case WEST:
+		c--
+	case EAST:
+		c++
+	}
+	if r >= 0 && r < size && c >= 0 && c < size {
+		return face, r, c
+	}
+
+	last := size - 1
+	switch face*4 + int(dir) {
+	case 0*4 + int(NORTH):
+		return 4, 0, last - col
+	case 0*4 + int(EAST):
+		return 3, 0, last - row
+	case 0*4 + int(SOUTH):
+		return 2, 0, col
+	case 0*4 + int(WEST):
+		return 1, 0, row
+
+	case 1*4 + int(NORTH):
+		return 0, col, 0
+	case 1*4 + int(EAST):
+		return 2, row, 0
+	case 1*4 + int(SOUTH):
+		return 5, last - col, 0
+	case 1*4 + int(WEST):
+		return 4, row, last
+
+	case 2*4 + int(NORTH):
+		return 0, last, col
+	case 2*4 + int(EAST):
+		return 3, row, 0
+	case 2*4 + int(SOUTH):
+		return 5, 0, col
+	case 2*4 + int(WEST):
+		return 1, row, last
+
+	case 3*4 + int(NORTH):
+		return 0, last - col, last
+	case 3*4 + int(EAST):
+		return 4, row, 0
+	case 3*4 + int(SOUTH):
+		return 5, col, last
+	case 3*4 + int(WEST):
+		return 2, row, last
+
+	case 4*4 + int(NORTH):
+		return 0, 0, last - col
+	case 4*4 + int(EAST):
+		return 1, row, 0
+	case 4*4 + int(SOUTH):
+		return 5, last, last - col
+	case 4*4 + int(WEST):
+		return 3, row, last
+
+	case 5*4 + int(NORTH):
+		return 2, last, col
+	case 5*4 + int(EAST):
+		return 3, last, row
+	case 5*4 + int(SOUTH):
+		return 4, last, last - col
+	default: // 5 WEST
+		return 1, last, last - row
+	}
+}
+
+// Side returns the number of rows (and columns) on each face
+func (g Cube) Side() int {
+	return g.faces[0].Rows()
+}
+
+// Face returns a single face of the cube. Its cells are still linked to the faces around it
+func (g Cube) Face(face int) Section {
+	return Section{g.faces[face]}
+}
+
+func (g Cube) Size() int {
+	return 6 * g.faces[0].Size()
+}
+
+func (g Cube) Directions() []Direction {
+	return orthogonal
+}
+
+func (g Cube) Connect(face, row, col int, dir Direction) {
+	g.Cell(face, row, col).connect(dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell in dir, but not back
+func (g Cube) ConnectOneWay(face, row, col int, dir Direction) {
+	g.Cell(face, row, col).connectOneWay(dir)
+}
+
+func (g Cube) Disconnect(face, row, col int, dir Direction) {
+	g.Cell(face, row, col).disconnect(dir)
+}
+
+func (g Cube) ConnectCell(c Cell, dir Direction) {
+	c.connect(dir)
+}
+
+func (g Cube) Cell(face, row, col int) Cell {
+	return g.faces[face].Cell(row, col)
+}
+
+func (g Cube) CellForIndex(idx int) Cell {
+	checkIndex(idx, g.Size())
+	return g.faces[0].store.cell(idx)
+}
+
+func (g Cube) CellDir(a, b Cell) Direction {
+	return cellDir(orthogonal, a, b)
+}
+
+// String prints the unfolded cube. Openings along the edge of a face lead onto the face next to it on the cube
+func (g Cube) String() string {
+	faces := make([][]string, len(g.faces))
+	for f, face := range g.faces {
+		faces[f] = strings.Split(strings.TrimSuffix(face.String(), "\n"), "\n")
+	}
+	blank := strings.Repeat(" ", len(faces[0][0]))
+
+	var builder strings.Builder
+	for netRow := 0; netRow < 3; netRow++ {
+		for line := range faces[0] {
+			var row []string
+			for netCol := 0; netCol < 4; netCol++ {
+				text := blank
+				for f, pos := range netPositions {
+					if pos == [2]int{netRow, netCol} {
+						text = faces[f][line]
+					}
+				}
+				row = append(row, text)
+			}
+			builder.WriteString(strings.TrimRight(strings.Join(row, " "), " ") + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// faceSize returns the area each face is drawn in when the unfolded cube is drawn in size
+func (g Cube) faceSize(size pixel.Rect) pixel.Rect {
+	side := size.W() / 4
+	if size.H()/3 < side {
+		side = size.H() / 3
+	}
+	return pixel.R(0, 0, side, side)
+}
+
+// faceOffset returns how far the face is moved over in the unfolded cube
+func (g Cube) faceOffset(face int, faceSize pixel.Rect) pixel.Vec {
+	return pixel.V(float64(netPositions[face][1])*faceSize.W(), float64(2-netPositions[face][0])*faceSize.H())
+}
+
+func (g Cube) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	faceSize := g.faceSize(size)
+	return g.faces[c.Level()].CellRect(c, faceSize, thickness).Moved(g.faceOffset(c.Level(), faceSize))
+}
+
+// Draw draws the unfolded cube
+func (g Cube) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+
+	faceSize := g.faceSize(size)
+	for f, face := range g.faces {
+		face.drawWalls(target, g.faceOffset(f, faceSize), faceSize, thickness)
+	}
+
+	drawPortals(target, g, g.faces[0].store, size, thickness)
+	drawOneWay(target, g, size, thickness)
+
+	target.Draw(window)
+}
@@ -0,0 +1,32 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestCubeSeams(t *testing.T) {
+	for size := 1; size <= 4; size++ {
+		g := NewCube(size)
+		checkNeighbors(t, g)
+
+		// every cell has all four neighbors, even along the edges of its face
+		for i := 0; i < g.Size(); i++ {
+			for _, d := range orthogonal {
+				if g.CellForIndex(i).neighbor(d) < 0 {
+					t.Fatalf("size %d: cell %d has no neighbor in direction %d", size, i, d)
+				}
+			}
+		}
+	}
+}
+
+func TestFaceCopy(t *testing.T) {
+	g := NewCube(3)
+	g.Connect(2, 0, 0, WEST)
+	g.Connect(2, 0, 0, EAST)
+
+	face := g.Face(2).Copy()
+	if face.Size() != 9 || !face.Cell(0, 0).Connected(EAST) || face.Cell(0, 0).Connected(WEST) {
+		t.Fatalf("expected a copy of the face without the link off its edge, found\n%s", face)
+	}
+}
@@ -0,0 +1,162 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"math"
+)
+
+// triangle is three point indexes along with the circle through them
+type triangle struct {
+	corners [3]int
+	center  pixel.Vec
+	radius2 float64 // squared
+}
+
+func newTriangle(points []pixel.Vec, a, b, c int) triangle {
+	pa, pb, pc := points[a], points[b], points[c]
+	d := 2 * (pa.X*(pb.Y-pc.Y) + pb.X*(pc.Y-pa.Y) + pc.X*(pa.Y-pb.Y))
+	if d == 0 { // the points are in a line, so the circle is infinitely big
+		return triangle{corners: [3]int{a, b, c}, radius2: math.Inf(1)}
+	}
+	la, lb, lc := pa.Len()*pa.Len(), pb.Len()*pb.Len(), pc.Len()*pc.Len()
+	center := pixel.V(
+		(la*(pb.Y-pc.Y)+lb*(pc.Y-pa.Y)+lc*(pa.Y-pb.Y))/d,
+		(la*(pc.X-pb.X)+lb*(pa.X-pc.X)+lc*(pb.X-pa.X))/d,
+	)
+	r := center.Sub(pa).Len()
+
+	return triangle{corners: [3]int{a, b, c}, center: center, radius2: r * r}
+}
+
+// edge is a pair of point indexes, lower index first
+type edge [2]int
+
+func newEdge(a, b int) edge {
+	if a > b {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// orientation is positive when a, b and c go counterclockwise, negative when they go clockwise and 0 when they're in a line
+func orientation(a, b, c pixel.Vec) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// delaunay triangulates the points with the Bowyer-Watson algorithm, adding them one at a time and re-triangulating the hole each one makes
+// every point must be different. It returns the triangles, along with the triangles on each side of every edge between two points,
+// which is none for an edge that only touches the big triangle the points started in, like the edges between points that are all in a line
+func delaunay(points []pixel.Vec) ([]triangle, map[edge][]int) {
+	// start with a triangle big enough to hold every point, and take it away at the end
+	min, max := points[0], points[0]
+	for _, p := range points {
+		min = pixel.V(math.Min(min.X, p.X), math.Min(min.Y, p.Y))
+		max = pixel.V(math.Max(max.X, p.X), math.Max(max.Y, p.Y))
+	}
+	span := math.Max(max.X-min.X, max.Y-min.Y) + 1
+	mid := min.Add(max).Scaled(0.5)
+	n := len(points)
+	all := append(append([]pixel.Vec(nil), points...),
+		pixel.V(mid.X-20*span, mid.Y-span),
+		pixel.V(mid.X+20*span, mid.Y-span),
+		pixel.V(mid.X, mid.Y+20*span),
+	)
+
+	// corners go counterclockwise. Taken away triangles stay in the list, so the indexes in around don't move
+	var triangles []triangle
+	gone := make(map[int]bool)
+	around := make(map[edge][]int) // the triangles on each side of every edge
+	add := func(a, b, c int) {
+		for _, e := range [3]edge{newEdge(a, b), newEdge(b, c), newEdge(c, a)} {
+			around[e] = append(around[e], len(triangles))
+		}
+		triangles = append(triangles, newTriangle(all, a, b, c))
+	}
+	add(n, n+1, n+2)
+
+	for i := 0; i < n; i++ {
+		p := all[i]
+
+		// the hole starts with the triangle the point is in: the one it's farthest inside of
+		start, inside := -1, math.Inf(-1)
+		for t, tri := range triangles {
+			if gone[t] {
+				continue
+			}
+			c := tri.corners
+			if in := math.Min(orientation(all[c[0]], all[c[1]], p), math.Min(orientation(all[c[1]], all[c[2]], p), orientation(all[c[2]], all[c[0]], p))); in > inside {
+				start, inside = t, in
+			}
+		}
+
+		// and spreads to the triangles next to it whose circles hold the point,
+		// and past every edge of the hole the point is on or behind, or the new triangles would overlap
+		hole := []int{start}
+		inHole := map[int]bool{start: true}
+		for h := 0; h < len(hole); h++ {
+			c := triangles[hole[h]].corners
+			for j := 0; j < 3; j++ {
+				a, b := c[j], c[(j+1)%3]
+				for _, t := range around[newEdge(a, b)] {
+					if inHole[t] {
+						continue
+					}
+					if d := triangles[t].center.Sub(p); d.Dot(d) < triangles[t].radius2 || orientation(all[a], all[b], p) <= 0 {
+						hole = append(hole, t)
+						inHole[t] = true
+					}
+				}
+			}
+		}
+
+		// the point fills the hole with a triangle on each edge around it
+		var outline [][2]int
+		for _, t := range hole {
+			c := triangles[t].corners
+			for j := 0; j < 3; j++ {
+				a, b := c[j], c[(j+1)%3]
+				e := newEdge(a, b)
+				if len(around[e]) == 1 || !inHole[around[e][0]] || !inHole[around[e][1]] {
+					outline = append(outline, [2]int{a, b})
+				}
+			}
+		}
+		for _, t := range hole {
+			gone[t] = true
+			c := triangles[t].corners
+			for j := 0; j < 3; j++ {
+				e := newEdge(c[j], c[(j+1)%3])
+				if around[e] = without(around[e], t); len(around[e]) == 0 {
+					delete(around, e)
+				}
+			}
+		}
+		for _, o := range outline {
+			add(o[0], o[1], i)
+		}
+	}
+
+	var kept []triangle
+	sides := make(map[edge][]int)
+	for t, tri := range triangles {
+		if gone[t] {
+			continue
+		}
+		c := tri.corners
+		real := c[0] < n && c[1] < n && c[2] < n
+		for j := 0; j < 3; j++ {
+			if e := newEdge(c[j], c[(j+1)%3]); e[1] < n {
+				if real {
+					sides[e] = append(sides[e], len(kept))
+				} else if _, ok := sides[e]; !ok {
+					sides[e] = nil
+				}
+			}
+		}
+		if real {
+			kept = append(kept, tri)
+		}
+	}
+
+	return kept, sides
+}
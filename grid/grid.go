@@ -1,6 +1,7 @@
 package grid
 
 import (
+	"fmt"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"image/color"
@@ -8,126 +9,239 @@ import (
 )
 
 type Grid struct {
-	grid [][]Cell // grid[row][col] // origin in the top left
+	store      *store
+	offset     int // index of the top left cell, for grids that are one part of a bigger maze
+	rows, cols int // origin in the top left
+	directions []Direction
 }
 
 func New(rows, cols int) Grid {
-	g := Grid{
-		grid: make([][]Cell, rows),
-	}
-	gridMem := make([]Cell, rows*cols)
+	return newFlat(&flat{rows: rows, cols: cols}, orthogonal)
+}
 
-	for i := 0; i < rows; i++ {
-		g.grid[i] = gridMem[cols*i : cols*(i+1)]
+// newFlat returns a grid laid out by f, whose cells may link in directions
+func newFlat(f *flat, directions []Direction) Grid {
+	return Grid{
+		store:      newStore(f, f.rows*f.cols, directions),
+		rows:       f.rows,
+		cols:       f.cols,
+		directions: directions,
 	}
+}
 
-	var idx int
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			g.grid[r][c].index = idx
-			idx++
+// flat is the shape of a single rows x cols grid, along with the diagonals of an upsilon grid, the tunnels of a weave and any wrapping edges
+type flat struct {
+	rows, cols int
+	wrap       byte // wrap* flags
+	diagonals  bool // octagons have diagonal neighbors
+	tunnels    bool
+}
 
-			g.grid[r][c].row = r
-			g.grid[r][c].col = c
+func (f *flat) position(idx int) (int, int, int) {
+	return 0, idx / f.cols, idx % f.cols
+}
 
-			if r > 0 {
-				g.grid[r][c].addNeighbor(&g.grid[r-1][c], NORTH)
-			}
-			if r+1 < rows {
-				g.grid[r][c].addNeighbor(&g.grid[r+1][c], SOUTH)
-			}
-			if c > 0 {
-				g.grid[r][c].addNeighbor(&g.grid[r][c-1], WEST)
+func (f *flat) neighbor(idx int, dir Direction) int {
+	row, col := idx/f.cols, idx%f.cols
+	r, c := row, col
+	switch dir {
+	case NORTH:
+		r--
+	case EAST:
+		c++
+	case SOUTH:
+		r++
+	case WEST:
+		c--
+	case NORTHEAST, SOUTHEAST, SOUTHWEST, NORTHWEST:
+		if !f.diagonals || !IsOctagon(row, col) {
+			return -1
+		}
+		if dir == NORTHEAST || dir == NORTHWEST {
+			r--
+		} else {
+			r++
+		}
+		if dir == NORTHEAST || dir == SOUTHEAST {
+			c++
+		} else {
+			c--
+		}
+	case TUNNELNORTH, TUNNELEAST, TUNNELSOUTH, TUNNELWEST:
+		if !f.tunnels {
+			return -1
+		}
+		n := f.neighbor(idx, dir.surface())
+		if n < 0 || f.wrap != 0 {
+			return -1
+		}
+		return f.neighbor(n, dir.surface())
+	default:
+		return -1
+	}
+
+	if dir <= WEST {
+		// edges that wrap around
+		if (c < 0 || c == f.cols) && f.wrap&wrapEastWest != 0 {
+			c = (c + f.cols) % f.cols
+			if f.wrap&twistEastWest != 0 {
+				r = f.rows - 1 - r
 			}
-			if c+1 < cols {
-				g.grid[r][c].addNeighbor(&g.grid[r][c+1], EAST)
+		}
+		if (r < 0 || r == f.rows) && f.wrap&wrapNorthSouth != 0 {
+			r = (r + f.rows) % f.rows
+			if f.wrap&twistNorthSouth != 0 {
+				c = f.cols - 1 - c
 			}
 		}
 	}
-
-	return g
+	if r < 0 || r >= f.rows || c < 0 || c >= f.cols {
+		return -1
+	}
+	return r*f.cols + c
 }
 
 func (g Grid) Rows() int {
-	return len(g.grid)
+	return g.rows
 }
 
 func (g Grid) Cols() int {
-	return len(g.grid[0])
+	return g.cols
+}
+
+// Directions returns every direction a cell in this grid may have a neighbor in
+func (g Grid) Directions() []Direction {
+	return g.directions
+}
+
+func (g Grid) Size() int {
+	return g.Rows() * g.Cols()
 }
 
 func (g Grid) Connect(row, col int, dir Direction) {
-	g.grid[row][col].connect(dir)
+	g.Cell(row, col).connect(dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell at row, col in dir, but not back
+func (g Grid) ConnectOneWay(row, col int, dir Direction) {
+	g.Cell(row, col).connectOneWay(dir)
+}
+
+func (g Grid) ConnectCell(c Cell, dir Direction) {
+	c.connect(dir)
 }
 
 func (g Grid) Disconnect(row, col int, dir Direction) {
-	g.grid[row][col].disconnect(dir)
+	g.Cell(row, col).disconnect(dir)
 }
 
+// Cell returns the cell at row, col. It panics if there isn't one, rather than returning a cell from the next row
 func (g Grid) Cell(row, col int) Cell {
-	return g.grid[row][col]
+	if row < 0 || row >= g.rows || col < 0 || col >= g.cols {
+		panic(fmt.Sprintf("grid: cell %d, %d is outside the %dx%d grid", row, col, g.rows, g.cols))
+	}
+	return g.store.cell(g.offset + row*g.cols + col)
 }
 
 func (g Grid) CellForIndex(idx int) Cell {
-	return g.grid[idx/g.Rows()][idx%g.Rows()]
+	checkIndex(idx, g.Size())
+	return g.store.cell(g.offset + idx)
 }
 
 // CellDir returns the direction from a to b
 func (g Grid) CellDir(a, b Cell) Direction {
-	if a.col == b.col {
-		if a.Row() < b.Row() {
-			return SOUTH
+	return cellDir(g.directions, a, b)
+}
+
+func (g Grid) String() string {
+	return g.ascii(nil, nil)
+}
+
+// ascii renders the grid as text
+// corner returns the character to draw at the top left corner of each cell and body the 3 characters inside it
+// nil draws plain corners, and bodies with the letters of up to 3 portals in the cell
+func (g Grid) ascii(corner func(r, c int) string, body func(r, c int) string) string {
+	if corner == nil {
+		corner = func(r, c int) string {
+			return "+"
 		}
-		return NORTH
 	}
-	if a.Col() < b.Col() {
-		return EAST
+	if body == nil {
+		labels := make(map[int][]byte)
+		for i, pair := range g.store.portalPairs() {
+			for _, idx := range pair {
+				labels[idx] = append(labels[idx], portalLabel(i))
+			}
+		}
+		body = func(r, c int) string {
+			switch label := labels[g.Cell(r, c).index]; len(label) {
+			case 0:
+				return "   "
+			case 1:
+				return " " + string(label) + " "
+			case 2:
+				return string(label[0]) + " " + string(label[1])
+			case 3:
+				return string(label)
+			}
+			return "***" // too many to fit
+		}
 	}
-	return WEST
-}
 
-func (g Grid) String() string {
 	var builder strings.Builder
-	for r := 0; r < len(g.grid); r++ {
+	for r := 0; r < g.Rows(); r++ {
 		for i := 0; i < 2; i++ {
-			for c := 0; c < len(g.grid[r]); c++ {
+			for c := 0; c < g.Cols(); c++ {
 				switch i {
 				case 0:
-					if g.grid[r][c].Connected(NORTH) {
-						builder.WriteString("+   ")
-					} else {
-						builder.WriteString("+---")
-					}
+					builder.WriteString(corner(r, c))
+					builder.WriteString(wallText(g.Cell(r, c), NORTH, "---", "   ", " ^ ", " v "))
 				default:
-					if g.grid[r][c].Connected(WEST) {
-						builder.WriteString("    ")
-					} else {
-						builder.WriteString("|   ")
-					}
+					builder.WriteString(wallText(g.Cell(r, c), WEST, "|", " ", "<", ">"))
+					builder.WriteString(body(r, c))
 				}
 			}
 			switch i {
 			case 0:
 				builder.WriteString("+\n")
 			default:
-				builder.WriteString("|\n")
+				builder.WriteString(wallText(g.Cell(r, g.Cols()-1), EAST, "|", " ", ">", "<") + "\n") // only open if it wraps around
 			}
 		}
 	}
-	for c := 0; c < len(g.grid[0]); c++ {
-		builder.WriteString("+---")
+	for c := 0; c < g.Cols(); c++ {
+		builder.WriteString("+" + wallText(g.Cell(g.Rows()-1, c), SOUTH, "---", "   ", " v ", " ^ ")) // only open if it wraps around
 	}
 	builder.WriteString("+\n")
 
 	return builder.String()
 }
 
+// wallText returns the text for the dir side of cell: wall if it's closed, open if it's open both ways,
+// or out or in for a one-way passage leaving or entering the cell
+func wallText(cell Cell, dir Direction, wall, open, out, in string) string {
+	switch {
+	case !cell.open(dir):
+		return wall
+	case !cell.OneWay(dir):
+		return open
+	case cell.Connected(dir):
+		return out
+	}
+	return in
+}
+
 func (g Grid) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
 	target := imdraw.New(nil)
 	target.Color = color.White
-	target.Push(pixel.V(size.W()-thickness/2, size.H()+thickness), pixel.V(size.W()-thickness/2, 3*thickness/2), pixel.V(thickness, 3*thickness/2))
-	target.Line(thickness)
+	g.drawWalls(target, pixel.ZV, size, thickness)
+	drawPortals(target, g, g.store, size, thickness)
+	drawOneWay(target, g, size, thickness)
+	target.Draw(window)
+}
 
+// drawWalls draws the grid as if it were drawn in size, moved over by offset
+func (g Grid) drawWalls(target *imdraw.IMDraw, offset pixel.Vec, size pixel.Rect, thickness float64) {
 	cellWidth := (size.W() - thickness) / float64(g.Cols())
 	cellHeight := (size.H() - thickness) / float64(g.Rows())
 	for r := 0; r < g.Rows(); r++ {
@@ -136,36 +250,57 @@ func (g Grid) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
 			x := float64(c)*cellWidth + thickness // top left
 			cell := g.Cell(r, c)
 
-			if !cell.Connected(NORTH) {
-				target.Push(pixel.V(x, y), pixel.V(x+cellWidth, y))
+			// the south and east edges are only open if the grid wraps around
+			if c == g.Cols()-1 && !cell.open(EAST) {
+				bottom := y - cellHeight
+				if r == g.Rows()-1 {
+					bottom = thickness
+				}
+				target.Push(pixel.V(size.W()-thickness/2, y).Add(offset), pixel.V(size.W()-thickness/2, bottom).Add(offset))
+				target.Line(thickness)
+			}
+			if r == g.Rows()-1 && !cell.open(SOUTH) {
+				target.Push(pixel.V(x, 3*thickness/2).Add(offset), pixel.V(x+cellWidth, 3*thickness/2).Add(offset))
+				target.Line(thickness)
+			}
+
+			if !cell.open(NORTH) {
+				target.Push(pixel.V(x, y).Add(offset), pixel.V(x+cellWidth, y).Add(offset))
 				target.Line(thickness)
 			}
-			if !cell.Connected(WEST) {
-				target.Push(pixel.V(x, y), pixel.V(x, y-cellHeight))
+			if !cell.open(WEST) {
+				target.Push(pixel.V(x, y).Add(offset), pixel.V(x, y-cellHeight).Add(offset))
 				target.Line(thickness)
 			}
 		}
 	}
+}
 
-	target.Draw(window)
+func (g Grid) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	cellWidth := (size.W() - thickness) / float64(g.Cols())
+	cellHeight := (size.H() - thickness) / float64(g.Rows())
+	x := float64(c.Col())*cellWidth + thickness             // top left
+	y := float64(g.Rows()-c.Row())*cellHeight + thickness*2 // top left
+
+	return pixel.R(x, y-cellHeight, x+cellWidth, y)
 }
 
 type MazeStats struct {
-	DeadEnds int
-	FourWay int
-	Corridors int
+	DeadEnds  int `json:"deadEnds"`
+	FourWay   int `json:"fourWay"`
+	Corridors int `json:"corridors"`
 }
 
 func (g Grid) Statistics() MazeStats {
 	var stats MazeStats
 
 	for r := 0; r < g.Rows(); r++ {
-		for c := 0; c < g.Rows(); c++ {
+		for c := 0; c < g.Cols(); c++ {
 			cell := g.Cell(r, c)
 
 			var openings int
-			for d := NORTH; d <= WEST; d++ {
-				if cell.Connected(d) {
+			for _, d := range g.directions {
+				if cell.open(d) {
 					openings++
 				}
 			}
@@ -0,0 +1,191 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Grid3D stacks levels of rows x cols grids on top of each other, with cells linked UP and DOWN to the cells directly above and below them
+// level 0 is the bottom level
+type Grid3D struct {
+	levels []Grid
+}
+
+var directions3D = []Direction{NORTH, EAST, SOUTH, WEST, UP, DOWN}
+
+func New3D(levels, rows, cols int) Grid3D {
+	g := Grid3D{
+		levels: make([]Grid, levels),
+	}
+	st := newStore(stack{levels, rows, cols}, levels*rows*cols, directions3D)
+
+	for l := range g.levels {
+		g.levels[l] = Grid{
+			store:      st,
+			offset:     l * rows * cols,
+			rows:       rows,
+			cols:       cols,
+			directions: orthogonal,
+		}
+	}
+
+	return g
+}
+
+// stack is the shape of a 3D grid
+type stack struct {
+	levels, rows, cols int
+}
+
+func (s stack) position(idx int) (int, int, int) {
+	levelSize := s.rows * s.cols
+	return idx / levelSize, idx % levelSize / s.cols, idx % s.cols
+}
+
+func (s stack) neighbor(idx int, dir Direction) int {
+	l, r, c := s.position(idx)
+	switch dir {
+	case NORTH:
+		r--
+	case EAST:
+		c++
+	case SOUTH:
+		r++
+	case WEST:
+		c--
+	case UP:
+		l++
+	case DOWN:
+		l--
+	default:
+		return -1
+	}
+
+	if l < 0 || l >= s.levels || r < 0 || r >= s.rows || c < 0 || c >= s.cols {
+		return -1
+	}
+	return (l*s.rows+r)*s.cols + c
+}
+
+func (g Grid3D) Levels() int {
+	return len(g.levels)
+}
+
+func (g Grid3D) Rows() int {
+	return g.levels[0].Rows()
+}
+
+func (g Grid3D) Cols() int {
+	return g.levels[0].Cols()
+}
+
+// Level returns a single level of the grid. Its cells are still linked to the levels above and below it
+func (g Grid3D) Level(level int) Section {
+	return Section{g.levels[level]}
+}
+
+func (g Grid3D) Size() int {
+	return g.Levels() * g.levels[0].Size()
+}
+
+func (g Grid3D) Directions() []Direction {
+	return directions3D
+}
+
+func (g Grid3D) Connect(level, row, col int, dir Direction) {
+	g.Cell(level, row, col).connect(dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell in dir, but not back
+func (g Grid3D) ConnectOneWay(level, row, col int, dir Direction) {
+	g.Cell(level, row, col).connectOneWay(dir)
+}
+
+func (g Grid3D) Disconnect(level, row, col int, dir Direction) {
+	g.Cell(level, row, col).disconnect(dir)
+}
+
+func (g Grid3D) ConnectCell(c Cell, dir Direction) {
+	c.connect(dir)
+}
+
+func (g Grid3D) Cell(level, row, col int) Cell {
+	return g.levels[level].Cell(row, col)
+}
+
+func (g Grid3D) CellForIndex(idx int) Cell {
+	checkIndex(idx, g.Size())
+	return g.levels[0].store.cell(idx)
+}
+
+func (g Grid3D) CellDir(a, b Cell) Direction {
+	return cellDir(g.Directions(), a, b)
+}
+
+// String prints each level from the bottom up, marking stairs up with < and stairs down with >
+func (g Grid3D) String() string {
+	var builder strings.Builder
+	for l, level := range g.levels {
+		if l > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("level " + strconv.Itoa(l) + "\n")
+		builder.WriteString(level.ascii(nil, func(r, c int) string {
+			cell := level.Cell(r, c)
+			switch {
+			case cell.Connected(UP) && cell.Connected(DOWN):
+				return "< >"
+			case cell.Connected(UP):
+				return " < "
+			case cell.Connected(DOWN):
+				return " > "
+			}
+			return "   "
+		}))
+	}
+
+	return builder.String()
+}
+
+// levelSize returns the area each level is drawn in. Levels are drawn left to right from the bottom up
+func (g Grid3D) levelSize(size pixel.Rect) pixel.Rect {
+	return pixel.R(0, 0, size.W()/float64(g.Levels()), size.H())
+}
+
+func (g Grid3D) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	levelSize := g.levelSize(size)
+	return g.levels[c.Level()].CellRect(c, levelSize, thickness).Moved(pixel.V(levelSize.W()*float64(c.Level()), 0))
+}
+
+// Draw draws each level side by side, with an up arrow in cells that have stairs up and a down arrow in cells with stairs down
+func (g Grid3D) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+
+	levelSize := g.levelSize(size)
+	for l, level := range g.levels {
+		level.drawWalls(target, pixel.V(levelSize.W()*float64(l), 0), levelSize, thickness)
+	}
+
+	for i := 0; i < g.Size(); i++ {
+		cell := g.CellForIndex(i)
+		rect := g.CellRect(cell, size, thickness)
+		w, h := rect.W(), rect.H()
+		if cell.Connected(UP) { // in the top right of the cell
+			target.Push(rect.Min.Add(pixel.V(w*0.55, h*0.55)), rect.Min.Add(pixel.V(w*0.85, h*0.55)), rect.Min.Add(pixel.V(w*0.7, h*0.8)))
+			target.Polygon(0)
+		}
+		if cell.Connected(DOWN) { // in the bottom left of the cell
+			target.Push(rect.Min.Add(pixel.V(w*0.15, h*0.45)), rect.Min.Add(pixel.V(w*0.45, h*0.45)), rect.Min.Add(pixel.V(w*0.3, h*0.2)))
+			target.Polygon(0)
+		}
+	}
+
+	drawPortals(target, g, g.levels[0].store, size, thickness)
+	drawOneWay(target, g, size, thickness)
+
+	target.Draw(window)
+}
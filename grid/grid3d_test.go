@@ -0,0 +1,31 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestLevelCopy(t *testing.T) {
+	g := New3D(2, 3, 3)
+	level := g.Level(1)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 2; c++ {
+			level.Connect(r, c, EAST)
+		}
+		level.Connect(r, 0, DOWN)
+	}
+	level.Connect(0, 0, SOUTH)
+	level.Connect(1, 0, SOUTH)
+
+	copied := level.Copy()
+	for i := 0; i < copied.Size(); i++ {
+		if idx := copied.CellForIndex(i).Index(); idx != i {
+			t.Fatalf("cell %d of the copy has index %d", i, idx)
+		}
+	}
+	if v := Validate(copied); !v.Perfect() {
+		t.Fatalf("expected the copy of the level to be a perfect maze of its own, found %+v\n%s", v, copied)
+	}
+	if !g.Cell(1, 2, 0).Connected(DOWN) {
+		t.Fatal("expected the level to stay linked to the level below it")
+	}
+}
@@ -0,0 +1,199 @@
+package grid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Document is a grid along with how it was made, for tools outside of Go
+type Document struct {
+	Maze      Grid       `json:"maze"`
+	Algorithm string     `json:"algorithm,omitempty"`
+	Seed      int64      `json:"seed,omitempty"`
+	Start     *Position  `json:"start,omitempty"`
+	End       *Position  `json:"end,omitempty"`
+	Stats     *MazeStats `json:"stats,omitempty"`
+}
+
+// NewDocument wraps g in a document along with its statistics
+func NewDocument(g Grid) Document {
+	stats := g.Statistics()
+	return Document{
+		Maze:  g,
+		Stats: &stats,
+	}
+}
+
+type Position struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// Link is an opening between two cells
+type Link struct {
+	From   Position `json:"from"`
+	To     Position `json:"to"`
+	OneWay bool     `json:"oneWay,omitempty"` // only passable from From to To
+	// Side is the side of From the link leaves by: "north", "east", "south" or "west". Portals and diagonal passages don't have one
+	// it tells apart passages between the same two cells, like the two ways around a grid 2 cells wide that wraps
+	Side string `json:"side,omitempty"`
+}
+
+// sides name the sides of a cell in links
+var sides = map[Direction]string{NORTH: "north", EAST: "east", SOUTH: "south", WEST: "west"}
+
+// passage returns the link through the open dir side of cell
+func passage(cell Cell, dir Direction) Link {
+	n := cell.store.cell(cell.neighbor(dir))
+	from, to := Position{cell.Row(), cell.Col()}, Position{n.Row(), n.Col()}
+	side := dir
+	if !cell.Connected(dir) { // one-way into this cell
+		from, to = to, from
+		side = n.back(cell, dir)
+	}
+	return Link{From: from, To: to, OneWay: cell.OneWay(dir), Side: sides[side]}
+}
+
+// jsonGrid is the JSON layout of a grid. Each link is listed once, and wrapping edges describe how the grid's edges meet
+type jsonGrid struct {
+	Rows            int    `json:"rows"`
+	Cols            int    `json:"cols"`
+	WrapEastWest    bool   `json:"wrapEastWest,omitempty"`
+	TwistEastWest   bool   `json:"twistEastWest,omitempty"`
+	WrapNorthSouth  bool   `json:"wrapNorthSouth,omitempty"`
+	TwistNorthSouth bool   `json:"twistNorthSouth,omitempty"`
+	Links           []Link `json:"links"`
+	Portals         []Link `json:"portals,omitempty"`
+	// Layers lists the values in each layer. Numbers come back as float64s
+	Layers map[string][]jsonValue `json:"layers,omitempty"`
+}
+
+// jsonValue is a cell's value in a layer
+type jsonValue struct {
+	Cell  Position    `json:"cell"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON encodes the grid's dimensions, links, portals and layers
+// like MarshalBinary, only whole grids with links in the four compass directions (including wrapped grids) can be encoded
+func (g Grid) MarshalJSON() ([]byte, error) {
+	if len(g.directions) != len(orthogonal) {
+		return nil, errors.New("grid: only square grids can be marshaled")
+	}
+	if !g.standalone() {
+		return nil, errors.New("grid: a level or face of a bigger maze can't be marshaled on its own")
+	}
+
+	flags := g.wrapFlags()
+	doc := jsonGrid{
+		Rows:            g.Rows(),
+		Cols:            g.Cols(),
+		WrapEastWest:    flags&wrapEastWest != 0,
+		TwistEastWest:   flags&twistEastWest != 0,
+		WrapNorthSouth:  flags&wrapNorthSouth != 0,
+		TwistNorthSouth: flags&twistNorthSouth != 0,
+		Links:           make([]Link, 0),
+	}
+	for r := 0; r < g.Rows(); r++ {
+		for c := 0; c < g.Cols(); c++ {
+			cell := g.Cell(r, c)
+			for _, dir := range [2]Direction{EAST, SOUTH} {
+				if cell.open(dir) {
+					doc.Links = append(doc.Links, passage(cell, dir))
+				}
+			}
+		}
+	}
+
+	for _, pair := range g.store.portalPairs() {
+		doc.Portals = append(doc.Portals, g.portalLink(pair))
+	}
+
+	for _, name := range Layers(g) {
+		for _, cell := range LayerOf(g, name).Cells() {
+			if idx := cell.index - g.offset; idx >= 0 && idx < g.Size() {
+				if doc.Layers == nil {
+					doc.Layers = make(map[string][]jsonValue)
+				}
+				doc.Layers[name] = append(doc.Layers[name], jsonValue{Position{cell.Row(), cell.Col()}, cell.Value(name)})
+			}
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the grid with one decoded from data, which must have been made by MarshalJSON
+func (g *Grid) UnmarshalJSON(data []byte) error {
+	var doc jsonGrid
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Rows <= 0 || doc.Cols <= 0 {
+		return fmt.Errorf("grid: bad dimensions %dx%d", doc.Rows, doc.Cols)
+	}
+	if (doc.TwistEastWest && !doc.WrapEastWest) || (doc.TwistNorthSouth && !doc.WrapNorthSouth) {
+		return errors.New("grid: an edge can't be twisted without being wrapped")
+	}
+
+	grid := New(doc.Rows, doc.Cols)
+	if doc.WrapEastWest {
+		grid.wrapEastWest(doc.TwistEastWest)
+	}
+	if doc.WrapNorthSouth {
+		grid.wrapNorthSouth(doc.TwistNorthSouth)
+	}
+
+	for _, link := range doc.Links {
+		if !grid.contains(link.From) || !grid.contains(link.To) {
+			return fmt.Errorf("grid: link %v-%v is outside the grid", link.From, link.To)
+		}
+		from := grid.Cell(link.From.Row, link.From.Col)
+		to := grid.Cell(link.To.Row, link.To.Col)
+		dir := cellDir(orthogonal, from, to)
+		if link.Side != "" {
+			dir = -1
+			for d, name := range sides {
+				if name == link.Side {
+					dir = d
+				}
+			}
+			if dir < 0 {
+				return fmt.Errorf("grid: link %v-%v leaves by an unknown side %q", link.From, link.To, link.Side)
+			}
+		}
+		if from.neighbor(dir) != to.index {
+			return fmt.Errorf("grid: link %v-%v joins cells that aren't next to each other", link.From, link.To)
+		}
+		if link.OneWay {
+			from.connectOneWay(dir)
+		} else {
+			from.connect(dir)
+		}
+	}
+
+	for _, portal := range doc.Portals {
+		if !grid.contains(portal.From) || !grid.contains(portal.To) || portal.From == portal.To {
+			return fmt.Errorf("grid: portal %v-%v doesn't join two cells in the grid", portal.From, portal.To)
+		}
+		AddPortal(grid.Cell(portal.From.Row, portal.From.Col), grid.Cell(portal.To.Row, portal.To.Col))
+	}
+
+	for name, values := range doc.Layers {
+		for _, v := range values {
+			if !grid.contains(v.Cell) {
+				return fmt.Errorf("grid: layer %q has a value outside the grid at %v", name, v.Cell)
+			}
+			LayerOf(grid, name).Set(grid.Cell(v.Cell.Row, v.Cell.Col), v.Value)
+		}
+	}
+
+	*g = grid
+	return nil
+}
+
+// contains reports whether p is a cell in the grid
+func (g Grid) contains(p Position) bool {
+	return p.Row >= 0 && p.Row < g.Rows() && p.Col >= 0 && p.Col < g.Cols()
+}
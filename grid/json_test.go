@@ -0,0 +1,52 @@
+package grid
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, topology := range topologies {
+		for _, size := range [][2]int{{1, 1}, {1, 4}, {2, 2}, {2, 5}, {3, 2}, {6, 7}} {
+			for i := 0; i < 20; i++ {
+				g := topology.make(size[0], size[1])
+				randomLinks(g, random, true)
+				for p := random.Intn(3); p > 0; p-- {
+					AddPortal(g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size())))
+				}
+				LayerOf(g, "items").Set(g.CellForIndex(random.Intn(g.Size())), "key")
+
+				data, err := json.Marshal(g)
+				if err != nil {
+					t.Fatalf("%s %v: %v", topology.name, size, err)
+				}
+				var decoded Grid
+				if err := json.Unmarshal(data, &decoded); err != nil {
+					t.Fatalf("%s %v: %v", topology.name, size, err)
+				}
+				if !decoded.Equal(g) {
+					t.Fatalf("%s %v: decoded\n%s\nfrom\n%s\n%s", topology.name, size, decoded, g, data)
+				}
+				if len(LayerOf(decoded, "items").Cells()) != 1 {
+					t.Fatalf("%s %v: lost the layer in\n%s", topology.name, size, data)
+				}
+			}
+		}
+	}
+}
+
+func TestJSONWithoutSides(t *testing.T) {
+	var g Grid
+	if err := json.Unmarshal([]byte(`{"rows": 1, "cols": 2, "links": [{"from": {"row": 0, "col": 0}, "to": {"row": 0, "col": 1}}]}`), &g); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Cell(0, 0).Connected(EAST) || !g.Cell(0, 1).Connected(WEST) {
+		t.Fatalf("expected a link worked out from where the cells are, found\n%s", g)
+	}
+
+	if err := json.Unmarshal([]byte(`{"rows": 1, "cols": 2, "links": [{"from": {"row": 0, "col": 0}, "to": {"row": 0, "col": 1}, "side": "up"}]}`), &g); err == nil {
+		t.Fatal("expected an error for an unknown side")
+	}
+}
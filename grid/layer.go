@@ -0,0 +1,160 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"sort"
+)
+
+// Layer is a named set of values attached to the cells of a maze, like the items in each cell or what it's made of
+// the values belong to the maze, so they're copied, cropped and turned along with it, and any code holding one of its cells can read them
+// they're saved by MarshalJSON, but not by MarshalBinary
+type Layer struct {
+	store *store
+	name  string
+}
+
+// LayerOf returns the layer of m with the given name, which is empty until values are set in it
+// a maze without any cells has nowhere to keep values, so its layers are always empty
+func LayerOf(m Maze, name string) Layer {
+	if m.Size() == 0 {
+		return Layer{&store{}, name}
+	}
+	return Layer{m.CellForIndex(0).store, name}
+}
+
+// Layers returns the names of the layers of m with values in them, in order
+func Layers(m Maze) []string {
+	if m.Size() == 0 {
+		return nil
+	}
+	return m.CellForIndex(0).store.layerNames()
+}
+
+func (l Layer) Name() string {
+	return l.name
+}
+
+// Get returns the cell's value, or nil if it doesn't have one
+func (l Layer) Get(c Cell) interface{} {
+	return l.store.layers[l.name][c.index]
+}
+
+// Set sets the cell's value. Setting nil removes it
+func (l Layer) Set(c Cell, value interface{}) {
+	l.store.setValue(l.name, c.index, value)
+}
+
+// Has reports whether the cell has a value
+func (l Layer) Has(c Cell) bool {
+	_, ok := l.store.layers[l.name][c.index]
+	return ok
+}
+
+// Int returns the cell's value as an int, or 0 if it isn't a number
+// numbers read back from JSON are float64s, so those are converted too
+func (l Layer) Int(c Cell) int {
+	switch v := l.Get(c).(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Float returns the cell's value as a float64, or 0 if it isn't a number
+func (l Layer) Float(c Cell) float64 {
+	switch v := l.Get(c).(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// String returns the cell's value if it's a string, or "" if it isn't
+func (l Layer) String(c Cell) string {
+	s, _ := l.Get(c).(string)
+	return s
+}
+
+// Bool returns the cell's value if it's a bool, or false if it isn't
+func (l Layer) Bool(c Cell) bool {
+	b, _ := l.Get(c).(bool)
+	return b
+}
+
+// Cells returns the cells with values, in index order
+func (l Layer) Cells() []Cell {
+	indexes := make([]int, 0, len(l.store.layers[l.name]))
+	for idx := range l.store.layers[l.name] {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	cells := make([]Cell, len(indexes))
+	for i, idx := range indexes {
+		cells[i] = l.store.cell(idx)
+	}
+	return cells
+}
+
+// Clear removes every value in the layer
+func (l Layer) Clear() {
+	delete(l.store.layers, l.name)
+}
+
+// Draw fills in each cell of m with a value in the color colorOf picks for it, under the maze's walls
+// cells without a value are left alone
+func (l Layer) Draw(m Maze, window pixel.Target, size pixel.Rect, thickness float64, colorOf func(value interface{}) color.Color) {
+	target := imdraw.New(nil)
+
+	for i := 0; i < m.Size(); i++ {
+		cell := m.CellForIndex(i)
+		if !l.Has(cell) {
+			continue
+		}
+		rect := m.CellRect(cell, size, thickness)
+
+		target.Color = colorOf(l.Get(cell))
+		target.Push(rect.Min, rect.Max)
+		target.Rectangle(0)
+	}
+
+	target.Draw(window)
+}
+
+// Value returns the cell's value in the named layer, or nil if it doesn't have one
+func (c Cell) Value(layer string) interface{} {
+	return c.store.layers[layer][c.index]
+}
+
+func (s *store) setValue(layer string, idx int, value interface{}) {
+	if value == nil {
+		delete(s.layers[layer], idx)
+		if len(s.layers[layer]) == 0 {
+			delete(s.layers, layer)
+		}
+		return
+	}
+
+	if s.layers == nil {
+		s.layers = make(map[string]map[int]interface{})
+	}
+	if s.layers[layer] == nil {
+		s.layers[layer] = make(map[int]interface{})
+	}
+	s.layers[layer][idx] = value
+}
+
+func (s *store) layerNames() []string {
+	names := make([]string, 0, len(s.layers))
+	for name := range s.layers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
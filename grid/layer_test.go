@@ -0,0 +1,39 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestLayer(t *testing.T) {
+	g := NewCube(2)
+	layer := LayerOf(g, "items")
+	layer.Set(g.Cell(3, 1, 0), "key")
+	layer.Set(g.Cell(0, 0, 0), 2)
+
+	if names := Layers(g); len(names) != 1 || names[0] != "items" {
+		t.Fatalf("expected just the items layer, found %v", names)
+	}
+	if layer.String(g.Cell(3, 1, 0)) != "key" || layer.Int(g.Cell(0, 0, 0)) != 2 || layer.Has(g.Cell(1, 0, 0)) {
+		t.Fatal("expected to read back the values that were set")
+	}
+	if cells := layer.Cells(); len(cells) != 2 || cells[0] != g.Cell(0, 0, 0) || cells[1] != g.Cell(3, 1, 0) {
+		t.Fatalf("expected the cells with values in index order, found %v", cells)
+	}
+
+	layer.Set(g.Cell(0, 0, 0), nil)
+	layer.Set(g.Cell(3, 1, 0), nil)
+	if names := Layers(g); len(names) != 0 {
+		t.Fatalf("expected removing every value to remove the layer, found %v", names)
+	}
+}
+
+func TestLayerEmptyMaze(t *testing.T) {
+	for _, m := range []Maze{New(0, 0), New3D(1, 0, 4), NewPlanar(nil)} {
+		if names := Layers(m); len(names) != 0 {
+			t.Fatalf("expected no layers, found %v", names)
+		}
+		if cells := LayerOf(m, "items").Cells(); len(cells) != 0 {
+			t.Fatalf("expected no values, found %v", cells)
+		}
+	}
+}
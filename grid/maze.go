@@ -0,0 +1,49 @@
+package grid
+
+import (
+	"fmt"
+	"github.com/faiface/pixel"
+)
+
+// Maze is implemented by every shape of grid the generators and solvers can work on
+// cells are identified by their index, which runs from 0 to Size()-1
+type Maze interface {
+	Size() int
+	// Directions returns every direction a cell may have a neighbor in
+	Directions() []Direction
+	CellForIndex(idx int) Cell
+	// CellDir returns the direction from a to its neighbor b
+	CellDir(a, b Cell) Direction
+	ConnectCell(c Cell, dir Direction)
+	// CellRect returns the area a cell is drawn in when the maze is drawn in size
+	CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect
+}
+
+// sameDirections reports whether two mazes' cells have neighbors in the same directions
+func sameDirections(a, b []Direction) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, d := range a {
+		if b[i] != d {
+			return false
+		}
+	}
+	return true
+}
+
+// checkIndex panics if idx isn't the index of one of a maze's size cells, the way indexing a slice of them would
+func checkIndex(idx, size int) {
+	if idx < 0 || idx >= size {
+		panic(fmt.Sprintf("grid: cell index %d is out of range for a maze of %d cells", idx, size))
+	}
+}
+
+func cellDir(directions []Direction, a, b Cell) Direction {
+	for _, d := range directions {
+		if n := a.Neighbor(d); n != nil && n.index == b.index {
+			return d
+		}
+	}
+	return NORTH
+}
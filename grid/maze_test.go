@@ -0,0 +1,92 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// topologies are the ways a flat grid's edges can meet, along with the seams ParseSeams needs to read them back
+var topologies = []struct {
+	name                 string
+	make                 func(rows, cols int) Grid
+	eastWest, northSouth Seam
+}{
+	{"flat", New, NoSeam, NoSeam},
+	{"cylinder", NewCylinder, StraightSeam, NoSeam},
+	{"torus", NewTorus, StraightSeam, StraightSeam},
+	{"mobius", NewMobius, TwistedSeam, NoSeam},
+	{"klein", NewKlein, StraightSeam, TwistedSeam},
+}
+
+// randomLinks links about half the sides of each cell of g, some of them one way if oneWay is set
+func randomLinks(g Grid, random *rand.Rand, oneWay bool) {
+	for idx := 0; idx < g.Size(); idx++ {
+		cell := g.CellForIndex(idx)
+		for _, d := range []Direction{EAST, SOUTH} {
+			if cell.neighbor(d) < 0 || cell.neighbor(d) == idx {
+				continue
+			}
+			switch random.Intn(4) {
+			case 0:
+				cell.connect(d)
+			case 1:
+				if oneWay {
+					cell.connectOneWay(d)
+				} else {
+					cell.connect(d)
+				}
+			}
+		}
+	}
+}
+
+// checkNeighbors checks that every cell of m is its neighbors' neighbor, and that linking a cell to a neighbor links the neighbor back and nothing else
+// m must start out without any links
+func checkNeighbors(t *testing.T, m Maze) {
+	t.Helper()
+	for i := 0; i < m.Size(); i++ {
+		cell := m.CellForIndex(i)
+		if cell.Index() != i {
+			t.Fatalf("cell %d has index %d", i, cell.Index())
+		}
+		for _, d := range m.Directions() {
+			n := cell.neighbor(d)
+			if n < 0 {
+				continue
+			}
+			if n >= m.Size() {
+				t.Fatalf("the neighbor of cell %d in direction %d is %d, outside the maze", i, d, n)
+			}
+			neighbor := m.CellForIndex(n)
+			back := neighbor.back(cell, d)
+			if neighbor.neighbor(back) != i {
+				t.Fatalf("cell %d is next to cell %d in direction %d, but not the other way around", i, n, d)
+			}
+
+			cell.connect(d)
+			if !neighbor.Connected(back) {
+				t.Fatalf("linking cell %d to cell %d in direction %d didn't link it back", i, n, d)
+			}
+			if links := countLinks(m); links != 2 {
+				t.Fatalf("linking cell %d to cell %d in direction %d set %d sides instead of 2", i, n, d, links)
+			}
+			cell.disconnect(d)
+			if neighbor.Connected(back) {
+				t.Fatalf("unlinking cell %d from cell %d in direction %d didn't unlink it back", i, n, d)
+			}
+		}
+	}
+}
+
+// countLinks counts the sides of cells in m that are linked
+func countLinks(m Maze) int {
+	var count int
+	for i := 0; i < m.Size(); i++ {
+		for _, d := range m.Directions() {
+			if m.CellForIndex(i).Connected(d) {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,49 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+)
+
+// arrowDirections points from the center of a cell toward its side in each direction, with north up
+var arrowDirections = map[Direction]pixel.Vec{
+	NORTH:       pixel.V(0, 1),
+	EAST:        pixel.V(1, 0),
+	SOUTH:       pixel.V(0, -1),
+	WEST:        pixel.V(-1, 0),
+	NORTHEAST:   pixel.V(math.Sqrt2/2, math.Sqrt2/2),
+	SOUTHEAST:   pixel.V(math.Sqrt2/2, -math.Sqrt2/2),
+	SOUTHWEST:   pixel.V(-math.Sqrt2/2, -math.Sqrt2/2),
+	NORTHWEST:   pixel.V(-math.Sqrt2/2, math.Sqrt2/2),
+	TUNNELNORTH: pixel.V(0, 1),
+	TUNNELEAST:  pixel.V(1, 0),
+	TUNNELSOUTH: pixel.V(0, -1),
+	TUNNELWEST:  pixel.V(-1, 0),
+}
+
+var oneWayColor = color.RGBA{R: 255, G: 140, B: 0, A: 255}
+
+// drawOneWay draws an arrow in each cell a one-way passage leaves from, pointing the way it goes
+func drawOneWay(target *imdraw.IMDraw, m Maze, size pixel.Rect, thickness float64) {
+	target.Color = oneWayColor
+	for i := 0; i < m.Size(); i++ {
+		cell := m.CellForIndex(i)
+		for _, d := range m.Directions() {
+			v, ok := arrowDirections[d]
+			if !ok || !cell.Connected(d) || !cell.OneWay(d) {
+				continue
+			}
+
+			rect := m.CellRect(cell, size, thickness)
+			scale := pixel.V(rect.W()/2, rect.H()/2)
+			center := rect.Center()
+			side := v.Normal().ScaledXY(scale).Scaled(0.25)
+			base := center.Add(v.ScaledXY(scale).Scaled(0.3))
+			target.Push(center.Add(v.ScaledXY(scale).Scaled(0.8)), base.Add(side), base.Sub(side))
+			target.Polygon(0)
+		}
+	}
+	target.Color = color.White
+}
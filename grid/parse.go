@@ -0,0 +1,211 @@
+package grid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError describes what's wrong with text passed to Parse. Line and Col count from 1
+type ParseError struct {
+	Line, Col int
+	Msg       string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("grid: line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+// Seam says how a pair of opposite edges of a parsed grid meet
+type Seam int
+
+const (
+	GuessSeam    Seam = iota // work it out from the openings in the outer wall
+	NoSeam                   // the edges don't meet
+	StraightSeam             // the edges meet straight across, like a cylinder
+	TwistedSeam              // the edges meet flipped over, like a Möbius strip
+)
+
+// Parse rebuilds a grid from the text printed by Grid.String, including the arrows on one-way passages
+// a letter inside a cell is one end of a portal, and has to be in exactly one other cell too. Anything else inside a cell is ignored. Openings in the outer wall wrap around to the other side of the grid,
+// and have to line up with openings on the other side (either straight across or flipped over)
+// without any openings the text can't tell a wrapped grid from a flat one, so Parse returns a flat grid,
+// and when the openings line up both ways it returns an error. ParseSeams says how the edges meet instead of guessing
+func Parse(text string) (Grid, error) {
+	return ParseSeams(text, GuessSeam, GuessSeam)
+}
+
+// ParseSeams is Parse for a grid whose east and west edges meet like eastWest says and whose north and south edges meet like northSouth says
+func ParseSeams(text string, eastWest, northSouth Seam) (Grid, error) {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	if len(lines) < 3 || len(lines)%2 == 0 {
+		return Grid{}, &ParseError{len(lines), 1, fmt.Sprintf("expected an odd number of lines (at least 3), found %d", len(lines))}
+	}
+	width := len(lines[0])
+	if width < 5 || width%4 != 1 {
+		return Grid{}, &ParseError{1, 1, fmt.Sprintf("expected lines 4 characters per cell plus 1 long, found %d", width)}
+	}
+	for i, line := range lines {
+		if len(line) != width {
+			return Grid{}, &ParseError{i + 1, 1, fmt.Sprintf("expected a line %d characters long like the first, found %d", width, len(line))}
+		}
+	}
+
+	rows, cols := len(lines)/2, width/4
+	g := New(rows, cols)
+
+	// horizontal[r][c] is the wall along the north side of the cell at r, c. Row rows is the south edge of the grid
+	// vertical[r][c] is the wall along the west side of the cell at r, c. Column cols is the east edge of the grid
+	horizontal := make([][]string, rows+1)
+	vertical := make([][]string, rows)
+	for i, line := range lines {
+		r := i / 2
+		if i%2 == 0 {
+			horizontal[r] = make([]string, cols)
+		} else {
+			vertical[r] = make([]string, cols+1)
+		}
+		for c := 0; c <= cols; c++ {
+			x := 4 * c
+			if i%2 == 1 {
+				vertical[r][c] = line[x : x+1]
+				if !strings.Contains("| <>", vertical[r][c]) {
+					return Grid{}, &ParseError{i + 1, x + 1, fmt.Sprintf("expected '|', ' ', '<' or '>' between cells, found %q", line[x])}
+				}
+				continue
+			}
+
+			if line[x] != '+' {
+				return Grid{}, &ParseError{i + 1, x + 1, fmt.Sprintf("expected '+' at the corner of a cell, found %q", line[x])}
+			}
+			if c == cols {
+				continue
+			}
+			switch horizontal[r][c] = line[x+1 : x+4]; horizontal[r][c] {
+			case "---", "   ", " ^ ", " v ":
+			default:
+				return Grid{}, &ParseError{i + 1, x + 2, fmt.Sprintf("expected \"---\", \"   \", \" ^ \" or \" v \" along a wall, found %q", horizontal[r][c])}
+			}
+		}
+	}
+
+	// the outer wall
+	if wrap, twist, r, msg := matchEdges(rows, "|", eastWest, "east", "west", func(r int) string { return vertical[r][cols] }, func(r int) string { return vertical[r][0] }); msg != "" {
+		return Grid{}, &ParseError{2*r + 2, width, msg}
+	} else if wrap {
+		g.wrapEastWest(twist)
+	}
+	if wrap, twist, c, msg := matchEdges(cols, "---", northSouth, "south", "north", func(c int) string { return horizontal[rows][c] }, func(c int) string { return horizontal[0][c] }); msg != "" {
+		return Grid{}, &ParseError{len(lines), 4*c + 2, msg}
+	} else if wrap {
+		g.wrapNorthSouth(twist)
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			cell := g.Cell(r, c)
+			parseLink(cell, EAST, vertical[r][c+1], " ", ">", "<")
+			parseLink(cell, SOUTH, horizontal[r+1][c], "   ", " v ", " ^ ")
+		}
+	}
+
+	// the letters inside cells are the ends of portals
+	type end struct {
+		cell      Cell
+		line, col int
+	}
+	ends := make(map[byte][]end)
+	var labels []byte
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			for x := 4*c + 1; x < 4*c+4; x++ {
+				label := lines[2*r+1][x]
+				if label == '*' {
+					return Grid{}, &ParseError{2*r + 2, x + 1, "'*' stands in for portals that couldn't be printed, so it's not clear where they go"}
+				}
+				if strings.IndexByte(portalLabels, label) < 0 {
+					continue
+				}
+				if len(ends[label]) == 0 {
+					labels = append(labels, label)
+				}
+				ends[label] = append(ends[label], end{g.Cell(r, c), 2*r + 2, x + 1})
+			}
+		}
+	}
+	for _, label := range labels {
+		e := ends[label]
+		switch {
+		case len(e) != 2:
+			return Grid{}, &ParseError{e[0].line, e[0].col, fmt.Sprintf("portal %q has %d ends, expected 2", label, len(e))}
+		case e[0].cell == e[1].cell:
+			return Grid{}, &ParseError{e[1].line, e[1].col, fmt.Sprintf("portal %q joins a cell to itself", label)}
+		}
+		AddPortal(e[0].cell, e[1].cell)
+	}
+
+	return g, nil
+}
+
+// parseLink links cell to its neighbor in dir if text says they're linked: open both ways, out from cell or in to it
+func parseLink(cell Cell, dir Direction, text, open, out, in string) {
+	if cell.neighbor(dir) < 0 {
+		return
+	}
+	switch text {
+	case open:
+		cell.connect(dir)
+	case out:
+		cell.connectOneWay(dir)
+	case in:
+		n := cell.store.cell(cell.neighbor(dir))
+		n.connectOneWay(n.back(cell, dir))
+	}
+}
+
+// matchEdges works out how the far and near edges of length cells wrap around to each other from the walls along them, unless seam says how
+// if they can't meet that way, it returns why, along with the first cell on the far edge that's out of place
+func matchEdges(length int, wall string, seam Seam, farName, nearName string, far, near func(i int) string) (wrap, twist bool, bad int, msg string) {
+	straight, flipped, open := -1, -1, -1
+	for i := 0; i < length; i++ {
+		if open < 0 && (far(i) != wall || near(i) != wall) {
+			open = i
+		}
+		if straight < 0 && far(i) != near(i) {
+			straight = i
+		}
+		if flipped < 0 && far(i) != near(length-1-i) {
+			flipped = i
+		}
+	}
+	mismatch := fmt.Sprintf("the %s wall here doesn't match the %s wall on the other side of the grid", farName, nearName)
+
+	switch seam {
+	case NoSeam:
+		if open >= 0 {
+			return false, false, open, fmt.Sprintf("the %s and %s edges don't meet, so their walls can't have openings", farName, nearName)
+		}
+		return false, false, 0, ""
+	case StraightSeam:
+		if straight >= 0 {
+			return false, false, straight, mismatch
+		}
+		return true, false, 0, ""
+	case TwistedSeam:
+		if flipped >= 0 {
+			return false, false, flipped, mismatch
+		}
+		return true, true, 0, ""
+	}
+
+	switch {
+	case open < 0:
+		return false, false, 0, ""
+	case straight < 0 && flipped < 0 && length > 1:
+		return false, false, open, fmt.Sprintf("the openings in the %s and %s walls line up both straight across and flipped over, so it's not clear how they meet", farName, nearName)
+	case straight < 0:
+		return true, false, 0, ""
+	case flipped < 0:
+		return true, true, 0, ""
+	}
+	return false, false, straight, mismatch
+}
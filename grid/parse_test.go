@@ -0,0 +1,61 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, topology := range topologies {
+		for i := 0; i < 50; i++ {
+			g := topology.make(5, 7)
+			randomLinks(g, random, true)
+			for p := random.Intn(6); p > 0; p-- {
+				AddPortal(g.CellForIndex(random.Intn(g.Size())), g.CellForIndex(random.Intn(g.Size())))
+			}
+
+			parsed, err := ParseSeams(g.String(), topology.eastWest, topology.northSouth)
+			if err != nil {
+				t.Fatalf("%s: %v\n%s", topology.name, err, g)
+			}
+			if !parsed.Equal(g) {
+				t.Fatalf("%s: parsed\n%s\nfrom\n%s", topology.name, parsed, g)
+			}
+		}
+	}
+}
+
+func TestParseAmbiguousSeam(t *testing.T) {
+	m := NewMobius(4, 3)
+	m.Connect(0, 2, EAST)
+	m.Connect(3, 2, EAST)
+
+	if _, err := Parse(m.String()); err == nil {
+		t.Fatal("expected an error for openings that line up both straight across and flipped over")
+	}
+	parsed, err := ParseSeams(m.String(), TwistedSeam, NoSeam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(m) {
+		t.Fatalf("parsed\n%s\nfrom\n%s", parsed, m)
+	}
+}
+
+func TestParsePortals(t *testing.T) {
+	g := New(2, 2)
+	if err := AddPortal(g.Cell(0, 0), New(2, 2).Cell(1, 1)); err == nil {
+		t.Fatal("expected an error for a portal between two mazes")
+	}
+	for _, text := range []string{
+		"+---+---+\n| A |   |\n+---+---+\n|   |   |\n+---+---+\n",
+		"+---+---+\n| A | A |\n+---+---+\n| A |   |\n+---+---+\n",
+		"+---+---+\n|A A|   |\n+---+---+\n|   |   |\n+---+---+\n",
+		"+---+---+\n|***|   |\n+---+---+\n|   |   |\n+---+---+\n",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("expected an error parsing\n%s", text)
+		}
+	}
+}
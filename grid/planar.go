@@ -0,0 +1,371 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Planar is a maze on any set of points in the plane. Each point is a cell, and the walls around it are its Voronoi cell,
+// so cells are neighbors when their Voronoi cells share a wall, which is the same as being joined in the points' Delaunay triangulation
+// a cell's directions don't point anywhere in particular: they name its neighbors in order, clockwise from north,
+// so generators that go by which way a direction points, like BinarySearch and Sidewinder, panic on one. A cell has at most 10 neighbors
+// a planar cell's Row() is its index
+type Planar struct {
+	store *store
+	shape *graph
+}
+
+// planarDirections name up to 10 neighbors of a planar cell, in clockwise order
+var planarDirections = []Direction{NORTH, NORTHEAST, EAST, SOUTHEAST, SOUTH, SOUTHWEST, WEST, NORTHWEST, UP, DOWN}
+
+// planarSlots is the position of each direction in planarDirections
+var planarSlots = func() [DOWN + 1]int {
+	var slots [DOWN + 1]int
+	for i := range slots {
+		slots[i] = -1
+	}
+	for i, d := range planarDirections {
+		slots[d] = i
+	}
+	return slots
+}()
+
+// graph is the shape of a planar maze
+type graph struct {
+	points    []pixel.Vec
+	neighbors [][]int     // the neighbors of each cell, clockwise from north
+	spacing   []float64   // distance from each cell to its nearest neighbor
+	walls     []planeWall // the Voronoi edge between every pair of cells that touch
+	min, max  pixel.Vec   // the walls are clipped to this box, which frames the maze
+}
+
+// planeWall separates cells a and b
+type planeWall struct {
+	a, b     int
+	from, to pixel.Vec
+}
+
+func (g *graph) position(idx int) (int, int, int) {
+	return 0, idx, 0
+}
+
+func (g *graph) neighbor(idx int, dir Direction) int {
+	if slot := planarSlots[dir]; slot >= 0 && slot < len(g.neighbors[idx]) {
+		return g.neighbors[idx][slot]
+	}
+	return -1
+}
+
+// NewPlanar makes a maze with a cell at each point. Repeated points are dropped
+// points in a line are joined one after another, with a straight wall halfway between each pair. A cell can have at most 10 neighbors, so a cell with more loses the ones farthest away
+func NewPlanar(points []pixel.Vec) Planar {
+	seen := make(map[pixel.Vec]bool, len(points))
+	var unique []pixel.Vec
+	for _, p := range points {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+
+	shape := &graph{
+		points:    unique,
+		neighbors: make([][]int, len(unique)),
+		spacing:   make([]float64, len(unique)),
+	}
+	var triangles []triangle
+	var sides map[edge][]int
+	if len(unique) >= 2 {
+		triangles, sides = delaunay(unique)
+	}
+
+	edges := make([]edge, 0, len(sides))
+	for e := range sides {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if a, b := shape.length(edges[i]), shape.length(edges[j]); a != b {
+			return a < b
+		}
+		return edges[i][0] < edges[j][0] || edges[i][0] == edges[j][0] && edges[i][1] < edges[j][1]
+	})
+	for _, e := range edges { // shortest first, so the cells that have too many neighbors drop the farthest
+		if len(shape.neighbors[e[0]]) < len(planarDirections) && len(shape.neighbors[e[1]]) < len(planarDirections) {
+			shape.neighbors[e[0]] = append(shape.neighbors[e[0]], e[1])
+			shape.neighbors[e[1]] = append(shape.neighbors[e[1]], e[0])
+		}
+	}
+
+	var total float64
+	for i, p := range unique {
+		shape.spacing[i] = math.Inf(1)
+		for _, n := range shape.neighbors[i] {
+			shape.spacing[i] = math.Min(shape.spacing[i], unique[n].Sub(p).Len())
+		}
+		if math.IsInf(shape.spacing[i], 1) {
+			shape.spacing[i] = 1
+		}
+		total += shape.spacing[i]
+
+		angle := func(n int) float64 { // clockwise from north
+			v := unique[n].Sub(p)
+			return math.Mod(math.Atan2(v.X, v.Y)+2*math.Pi, 2*math.Pi)
+		}
+		neighbors := shape.neighbors[i]
+		sort.Slice(neighbors, func(a, b int) bool {
+			return angle(neighbors[a]) < angle(neighbors[b])
+		})
+	}
+
+	if len(unique) > 0 {
+		pad := pixel.V(1, 1).Scaled(total / float64(len(unique)) / 2)
+		shape.min, shape.max = unique[0], unique[0]
+		for _, p := range unique {
+			shape.min = pixel.V(math.Min(shape.min.X, p.X), math.Min(shape.min.Y, p.Y))
+			shape.max = pixel.V(math.Max(shape.max.X, p.X), math.Max(shape.max.Y, p.Y))
+		}
+		shape.min, shape.max = shape.min.Sub(pad), shape.max.Add(pad)
+	}
+
+	for _, e := range edges {
+		w := planeWall{a: e[0], b: e[1]}
+		switch len(sides[e]) {
+		case 0:
+			// no triangles on either side, so the wall is the whole line halfway between the points
+			mid := unique[e[0]].Add(unique[e[1]]).Scaled(0.5)
+			along := unique[e[1]].Sub(unique[e[0]]).Normal().Unit().Scaled(shape.max.Sub(shape.min).Len() * 2)
+			w.from, w.to = mid.Sub(along), mid.Add(along)
+		case 1:
+			// an edge of the hull: the wall runs out of the maze, away from the triangle's third corner
+			t := triangles[sides[e][0]]
+			w.from = t.center
+			third := t.corners[0] + t.corners[1] + t.corners[2] - e[0] - e[1]
+			out := unique[e[1]].Sub(unique[e[0]]).Normal().Unit()
+			if out.Dot(unique[third].Sub(unique[e[0]])) > 0 {
+				out = out.Scaled(-1)
+			}
+			w.to = w.from.Add(out.Scaled(shape.max.Sub(shape.min).Len() * 2))
+		default:
+			w.from, w.to = triangles[sides[e][0]].center, triangles[sides[e][1]].center
+		}
+		if from, to, ok := clip(w.from, w.to, shape.min, shape.max); ok {
+			w.from, w.to = from, to
+			shape.walls = append(shape.walls, w)
+		}
+	}
+
+	return Planar{
+		store: newStore(shape, len(unique), planarDirections),
+		shape: shape,
+	}
+}
+
+func (g *graph) length(e edge) float64 {
+	return g.points[e[1]].Sub(g.points[e[0]]).Len()
+}
+
+// clip cuts the line from a to b down to the part inside the box from min to max (Liang-Barsky), returning false if none of it is inside
+func clip(a, b, min, max pixel.Vec) (pixel.Vec, pixel.Vec, bool) {
+	d := b.Sub(a)
+	t0, t1 := 0.0, 1.0
+	for _, side := range [4][2]float64{
+		{-d.X, a.X - min.X},
+		{d.X, max.X - a.X},
+		{-d.Y, a.Y - min.Y},
+		{d.Y, max.Y - a.Y},
+	} {
+		p, q := side[0], side[1]
+		if p == 0 {
+			if q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 && t > t0 {
+			t0 = t
+		} else if p > 0 && t < t1 {
+			t1 = t
+		}
+	}
+	if t0 > t1 {
+		return a, b, false
+	}
+	return a.Add(d.Scaled(t0)), a.Add(d.Scaled(t1)), true
+}
+
+// RandomPoints returns n points scattered uniformly over a width x height area
+func RandomPoints(n int, width, height float64) []pixel.Vec {
+	points := make([]pixel.Vec, n)
+	for i := range points {
+		points[i] = pixel.V(rand.Float64()*width, rand.Float64()*height)
+	}
+	return points
+}
+
+// PoissonPoints returns points scattered over a width x height area with no two closer than spacing (Bridson's algorithm)
+// the points are spread much more evenly than random points, which makes cells of similar sizes
+func PoissonPoints(spacing, width, height float64) []pixel.Vec {
+	const tries = 30
+
+	// each bucket is small enough to hold at most one point
+	bucketSize := spacing / math.Sqrt2
+	cols, rows := int(width/bucketSize)+1, int(height/bucketSize)+1
+	buckets := make([]int, rows*cols)
+	for i := range buckets {
+		buckets[i] = -1
+	}
+	bucket := func(p pixel.Vec) (int, int) {
+		return int(p.Y / bucketSize), int(p.X / bucketSize)
+	}
+
+	var points, active []pixel.Vec
+	add := func(p pixel.Vec) {
+		r, c := bucket(p)
+		buckets[r*cols+c] = len(points)
+		points = append(points, p)
+		active = append(active, p)
+	}
+	add(pixel.V(rand.Float64()*width, rand.Float64()*height))
+
+	for len(active) > 0 {
+		i := rand.Intn(len(active))
+		found := false
+		for try := 0; try < tries && !found; try++ {
+			// somewhere between spacing and twice spacing away
+			p := active[i].Add(pixel.V(spacing*(1+rand.Float64()), 0).Rotated(rand.Float64() * 2 * math.Pi))
+			if p.X < 0 || p.X >= width || p.Y < 0 || p.Y >= height {
+				continue
+			}
+
+			found = true
+			row, col := bucket(p)
+			for r := row - 2; r <= row+2 && found; r++ {
+				for c := col - 2; c <= col+2 && found; c++ {
+					if r >= 0 && r < rows && c >= 0 && c < cols && buckets[r*cols+c] >= 0 && points[buckets[r*cols+c]].Sub(p).Len() < spacing {
+						found = false
+					}
+				}
+			}
+			if found {
+				add(p)
+			}
+		}
+		if !found {
+			active[i] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+
+	return points
+}
+
+func (g Planar) Size() int {
+	return len(g.shape.points)
+}
+
+func (g Planar) Directions() []Direction {
+	return planarDirections
+}
+
+// Point returns where the cell is
+func (g Planar) Point(c Cell) pixel.Vec {
+	return g.shape.points[c.index]
+}
+
+func (g Planar) Connect(idx int, dir Direction) {
+	g.CellForIndex(idx).connect(dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell at idx in dir, but not back
+func (g Planar) ConnectOneWay(idx int, dir Direction) {
+	g.CellForIndex(idx).connectOneWay(dir)
+}
+
+func (g Planar) Disconnect(idx int, dir Direction) {
+	g.CellForIndex(idx).disconnect(dir)
+}
+
+func (g Planar) ConnectCell(c Cell, dir Direction) {
+	c.connect(dir)
+}
+
+func (g Planar) CellForIndex(idx int) Cell {
+	checkIndex(idx, g.Size())
+	return g.store.cell(idx)
+}
+
+func (g Planar) CellDir(a, b Cell) Direction {
+	return cellDir(planarDirections, a, b)
+}
+
+// transform returns the scale and offset that fit the maze's frame into size, leaving room for the walls
+func (g Planar) transform(size pixel.Rect, thickness float64) (float64, pixel.Vec) {
+	frame := g.shape.max.Sub(g.shape.min)
+	scale := math.Min((size.W()-thickness*2)/frame.X, (size.H()-thickness*2)/frame.Y)
+	return scale, size.Min.Add(pixel.V(thickness, thickness)).Sub(g.shape.min.Scaled(scale))
+}
+
+// CellRect returns a square around the cell's point, well inside its walls
+func (g Planar) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	scale, offset := g.transform(size, thickness)
+	center := g.shape.points[c.index].Scaled(scale).Add(offset)
+	half := g.shape.spacing[c.index] * scale * 0.35
+
+	return pixel.R(center.X-half, center.Y-half, center.X+half, center.Y+half)
+}
+
+// Draw draws the Voronoi walls between cells that aren't linked, inside a frame around the whole maze
+func (g Planar) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+	scale, offset := g.transform(size, thickness)
+
+	for _, w := range g.shape.walls {
+		a := g.store.cell(w.a)
+		if slot := g.slot(w.a, w.b); slot >= 0 && a.open(planarDirections[slot]) {
+			continue
+		}
+		target.Push(w.from.Scaled(scale).Add(offset), w.to.Scaled(scale).Add(offset))
+		target.Line(thickness)
+	}
+
+	min, max := g.shape.min.Scaled(scale).Add(offset), g.shape.max.Scaled(scale).Add(offset)
+	target.Push(min, pixel.V(max.X, min.Y), max, pixel.V(min.X, max.Y))
+	target.Polygon(thickness)
+
+	drawPortals(target, g, g.store, size, thickness)
+
+	// arrows point along one-way passages, toward the cell they lead to
+	target.Color = oneWayColor
+	for i := 0; i < g.Size(); i++ {
+		cell := g.store.cell(i)
+		for slot, n := range g.shape.neighbors[i] {
+			if d := planarDirections[slot]; !cell.Connected(d) || !cell.OneWay(d) {
+				continue
+			}
+			rect := g.CellRect(cell, size, thickness)
+			v := g.shape.points[n].Sub(g.shape.points[i]).Unit().Scaled(rect.W() / 2)
+			base := rect.Center().Add(v.Scaled(0.3))
+			target.Push(rect.Center().Add(v.Scaled(0.8)), base.Add(v.Normal().Scaled(0.25)), base.Sub(v.Normal().Scaled(0.25)))
+			target.Polygon(0)
+		}
+	}
+	target.Color = color.White
+
+	target.Draw(window)
+}
+
+// slot returns which of a's neighbors b is, or -1 if they aren't neighbors
+func (g Planar) slot(a, b int) int {
+	for slot, n := range g.shape.neighbors[a] {
+		if n == b {
+			return slot
+		}
+	}
+	return -1
+}
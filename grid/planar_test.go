@@ -0,0 +1,112 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// wheel is k points evenly spaced around a circle of the given radius, and its center
+func wheel(k int, radius float64) []pixel.Vec {
+	points := make([]pixel.Vec, 0, k+1)
+	for i := 0; i < k; i++ {
+		points = append(points, pixel.V(radius, 0).Rotated(float64(i)*2*math.Pi/float64(k)))
+	}
+	return append(points, pixel.ZV)
+}
+
+// lattice is a rows x cols square of points 1 apart
+func lattice(rows, cols int) []pixel.Vec {
+	var points []pixel.Vec
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			points = append(points, pixel.V(float64(c), float64(r)))
+		}
+	}
+	return points
+}
+
+func TestDelaunay(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	scattered := make([]pixel.Vec, 200)
+	for i := range scattered {
+		scattered[i] = pixel.V(random.Float64()*100, random.Float64()*100)
+	}
+
+	for name, points := range map[string][]pixel.Vec{
+		"scattered": scattered,
+		"lattice":   lattice(6, 9),
+		"wheel":     wheel(8, 1000),
+	} {
+		triangles, sides := delaunay(points)
+		for _, tri := range triangles {
+			c := tri.corners
+			if orientation(points[c[0]], points[c[1]], points[c[2]]) <= 0 {
+				t.Fatalf("%s: triangle %v doesn't go counterclockwise", name, c)
+			}
+			for i, p := range points {
+				if d := tri.center.Sub(p); d.Dot(d) < tri.radius2*(1-1e-9) {
+					t.Fatalf("%s: point %d is inside the circle of triangle %v", name, i, c)
+				}
+			}
+		}
+		for e, s := range sides {
+			if len(s) > 2 {
+				t.Fatalf("%s: edge %v has %d triangles along it", name, e, len(s))
+			}
+		}
+	}
+}
+
+func TestPlanarDegenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []pixel.Vec
+		edges  int
+	}{
+		{"none", nil, 0},
+		{"one", []pixel.Vec{pixel.V(1, 1)}, 0},
+		{"two", []pixel.Vec{pixel.V(1, 1), pixel.V(5, 3)}, 1},
+		{"repeated", []pixel.Vec{pixel.V(1, 1), pixel.V(1, 1), pixel.V(5, 3), pixel.V(1, 1)}, 1},
+		{"triangle", []pixel.Vec{pixel.V(0, 0), pixel.V(1, 0), pixel.V(0, 1)}, 3},
+		{"square", lattice(2, 2), 5},
+		{"row", []pixel.Vec{pixel.V(0, 0), pixel.V(3, 0), pixel.V(1, 0), pixel.V(7, 0), pixel.V(2, 0)}, 4},
+		{"column", []pixel.Vec{pixel.V(0, 0), pixel.V(0, 3), pixel.V(0, 1)}, 2},
+		{"diagonal", []pixel.Vec{pixel.V(0, 0), pixel.V(3, 3), pixel.V(1, 1), pixel.V(2, 2)}, 3},
+		{"lattice", lattice(8, 8), 161},
+		{"small wheel", wheel(8, 1), 16},
+		{"big wheel", wheel(9, 1000), 18},
+		{"crowded wheel", wheel(12, 1), 22}, // the center can only keep 10 of its 12 spokes
+	}
+
+	for _, test := range tests {
+		p := NewPlanar(test.points)
+		checkNeighbors(t, p)
+
+		edges := 0
+		for _, neighbors := range p.shape.neighbors {
+			edges += len(neighbors)
+		}
+		if edges/2 != test.edges {
+			t.Errorf("%s: expected %d pairs of neighbors, found %d", test.name, test.edges, edges/2)
+		}
+
+		// every cell can be reached from the first
+		if p.Size() == 0 {
+			continue
+		}
+		reached := map[int]bool{0: true}
+		for queue := []int{0}; len(queue) > 0; queue = queue[1:] {
+			for _, n := range p.shape.neighbors[queue[0]] {
+				if !reached[n] {
+					reached[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		if len(reached) != p.Size() {
+			t.Errorf("%s: only %d of %d cells can be reached", test.name, len(reached), p.Size())
+		}
+	}
+}
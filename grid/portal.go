@@ -0,0 +1,131 @@
+package grid
+
+import (
+	"errors"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// portalColors tells the ends of different portals apart when they're drawn
+var portalColors = []color.RGBA{
+	{R: 255, G: 64, B: 64, A: 255},
+	{R: 64, G: 160, B: 255, A: 255},
+	{R: 255, G: 200, B: 0, A: 255},
+	{R: 64, G: 220, B: 64, A: 255},
+	{R: 220, G: 64, B: 255, A: 255},
+	{R: 0, G: 220, B: 220, A: 255},
+}
+
+// portalLabels name the ends of different portals when they're printed
+const portalLabels = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// AddPortal links two different cells anywhere in the same maze, whether or not they're next to each other
+func AddPortal(a, b Cell) error {
+	if a.store == nil || a.store != b.store {
+		return errors.New("grid: a portal can only join two cells of the same maze")
+	}
+	a.store.addPortal(a.index, b.index)
+	return nil
+}
+
+// RemovePortal removes the portal between two cells of the same maze, if there is one
+func RemovePortal(a, b Cell) error {
+	if a.store == nil || a.store != b.store {
+		return errors.New("grid: a portal can only join two cells of the same maze")
+	}
+	a.store.removePortal(a.index, b.index)
+	return nil
+}
+
+// Portals returns the cells this cell is linked to through portals
+func (c Cell) Portals() []Cell {
+	var cells []Cell
+	for _, idx := range c.store.portals[c.index] {
+		cells = append(cells, c.store.cell(idx))
+	}
+	return cells
+}
+
+func (s *store) addPortal(a, b int) {
+	if a == b {
+		return
+	}
+	if s.portals == nil {
+		s.portals = make(map[int][]int)
+	}
+	for _, idx := range s.portals[a] {
+		if idx == b {
+			return
+		}
+	}
+	s.portals[a] = append(s.portals[a], b)
+	s.portals[b] = append(s.portals[b], a)
+}
+
+func (s *store) removePortal(a, b int) {
+	if s.portals == nil {
+		return
+	}
+	s.portals[a] = without(s.portals[a], b)
+	s.portals[b] = without(s.portals[b], a)
+	if len(s.portals[a]) == 0 {
+		delete(s.portals, a)
+	}
+	if len(s.portals[b]) == 0 {
+		delete(s.portals, b)
+	}
+}
+
+func without(list []int, value int) []int {
+	kept := list[:0]
+	for _, v := range list {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// portalPairs returns every portal once, as the indexes of the cells at each end (lower index first), in index order
+func (s *store) portalPairs() [][2]int {
+	var pairs [][2]int
+	for a, ends := range s.portals {
+		for _, b := range ends {
+			if a < b {
+				pairs = append(pairs, [2]int{a, b})
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	return pairs
+}
+
+// portalLabel returns the letter printed in cells at either end of the pairth portal
+func portalLabel(pair int) byte {
+	if pair >= len(portalLabels) {
+		return '*'
+	}
+	return portalLabels[pair]
+}
+
+// drawPortals marks both ends of each portal with a dot in the portal's color
+func drawPortals(target *imdraw.IMDraw, m Maze, s *store, size pixel.Rect, thickness float64) {
+	for i, pair := range s.portalPairs() {
+		target.Color = portalColors[i%len(portalColors)]
+		for _, idx := range pair {
+			rect := m.CellRect(s.cell(idx), size, thickness)
+			target.Push(rect.Center())
+			target.Circle(0.2*math.Min(rect.W(), rect.H()), 0)
+		}
+	}
+	target.Color = color.White
+}
@@ -0,0 +1,41 @@
+package grid
+
+// Section is one level of a 3D grid or one face of a cube: rows and columns of cells that are still linked to the rest of their maze
+// its cells keep their indexes in the whole maze, so it isn't a Maze of its own. Copy makes one from it
+type Section struct {
+	grid Grid
+}
+
+func (s Section) Rows() int {
+	return s.grid.Rows()
+}
+
+func (s Section) Cols() int {
+	return s.grid.Cols()
+}
+
+func (s Section) Cell(row, col int) Cell {
+	return s.grid.Cell(row, col)
+}
+
+func (s Section) Connect(row, col int, dir Direction) {
+	s.grid.Connect(row, col, dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell at row, col in dir, but not back
+func (s Section) ConnectOneWay(row, col int, dir Direction) {
+	s.grid.ConnectOneWay(row, col, dir)
+}
+
+func (s Section) Disconnect(row, col int, dir Direction) {
+	s.grid.Disconnect(row, col, dir)
+}
+
+// Copy returns a grid holding a copy of the section's cells, without the links leading off it to the rest of the maze
+func (s Section) Copy() Grid {
+	return s.grid.SubGrid(0, 0, s.Rows(), s.Cols())
+}
+
+func (s Section) String() string {
+	return s.grid.String()
+}
@@ -0,0 +1,263 @@
+package grid
+
+import (
+	"fmt"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Sphere covers a globe with rings of cells running east-west between the poles. Ring 0 circles the north pole
+// rings get longer toward the equator, always doubling (or halving) their length between neighbors,
+// so a cell next to a ring twice as long has two neighbors there: NORTHWEST and NORTHEAST or SOUTHWEST and SOUTHEAST
+// a cell's ring is its Row() and its place along the ring (counting east) is its Col()
+type Sphere struct {
+	store *store
+	shape globe
+}
+
+var sphereDirections = []Direction{NORTH, EAST, SOUTH, WEST, NORTHEAST, SOUTHEAST, SOUTHWEST, NORTHWEST}
+
+func NewSphere(rings int) Sphere {
+	var g Sphere
+
+	// aim for cells about as wide as they are tall
+	lengths := make([]int, rings)
+	for r := 0; r < (rings+1)/2; r++ {
+		latitude := math.Pi/2 - math.Pi*(float64(r)+0.5)/float64(rings)
+		ideal := 2 * float64(rings) * math.Cos(latitude)
+		if r == 0 {
+			lengths[r] = int(math.Max(3, math.Round(ideal)))
+		} else if ideal/float64(lengths[r-1]) >= 1.5 {
+			lengths[r] = lengths[r-1] * 2
+		} else {
+			lengths[r] = lengths[r-1]
+		}
+		lengths[rings-1-r] = lengths[r]
+	}
+
+	g.shape.starts = make([]int, rings+1)
+	for r, length := range lengths {
+		g.shape.starts[r+1] = g.shape.starts[r] + length
+	}
+	g.store = newStore(g.shape, g.Size(), sphereDirections)
+
+	return g
+}
+
+// globe is the shape of a sphere
+type globe struct {
+	starts []int // index of the first cell in each ring, then the number of cells
+}
+
+func (g globe) position(idx int) (int, int, int) {
+	ring := sort.Search(len(g.starts)-1, func(r int) bool {
+		return g.starts[r+1] > idx
+	})
+	return 0, ring, idx - g.starts[ring]
+}
+
+func (g globe) neighbor(idx int, dir Direction) int {
+	_, ring, col := g.position(idx)
+	length := g.starts[ring+1] - g.starts[ring]
+	switch dir {
+	case EAST:
+		return g.starts[ring] + (col+1)%length
+	case WEST:
+		return g.starts[ring] + (col+length-1)%length
+	case NORTH, NORTHWEST, NORTHEAST:
+		return g.across(ring, col, ring-1, dir, NORTH, NORTHWEST, NORTHEAST)
+	case SOUTH, SOUTHWEST, SOUTHEAST:
+		return g.across(ring, col, ring+1, dir, SOUTH, SOUTHWEST, SOUTHEAST)
+	}
+	return -1
+}
+
+// across returns the cell in the next ring over from ring, col in dir
+// that's either straight across or, if the next ring is twice as long, to the west and east
+func (g globe) across(ring, col, next int, dir, straight, west, east Direction) int {
+	if next < 0 || next >= len(g.starts)-1 {
+		return -1
+	}
+	length := g.starts[ring+1] - g.starts[ring]
+	nextLength := g.starts[next+1] - g.starts[next]
+	switch {
+	case nextLength > length && dir == west:
+		return g.starts[next] + col*2
+	case nextLength > length && dir == east:
+		return g.starts[next] + col*2 + 1
+	case nextLength == length && dir == straight:
+		return g.starts[next] + col
+	case nextLength < length && dir == straight:
+		return g.starts[next] + col/2
+	}
+	return -1
+}
+
+func (g Sphere) Rings() int {
+	return len(g.shape.starts) - 1
+}
+
+// RingSize returns the number of cells in ring
+func (g Sphere) RingSize(ring int) int {
+	return g.shape.starts[ring+1] - g.shape.starts[ring]
+}
+
+func (g Sphere) Size() int {
+	return g.shape.starts[g.Rings()]
+}
+
+func (g Sphere) Directions() []Direction {
+	return sphereDirections
+}
+
+func (g Sphere) Connect(ring, col int, dir Direction) {
+	g.Cell(ring, col).connect(dir)
+}
+
+// ConnectOneWay opens a passage that can be taken from the cell in dir, but not back
+func (g Sphere) ConnectOneWay(ring, col int, dir Direction) {
+	g.Cell(ring, col).connectOneWay(dir)
+}
+
+func (g Sphere) Disconnect(ring, col int, dir Direction) {
+	g.Cell(ring, col).disconnect(dir)
+}
+
+func (g Sphere) ConnectCell(c Cell, dir Direction) {
+	c.connect(dir)
+}
+
+// Cell returns the cell at col along ring. It panics if there isn't one, rather than returning a cell from the next ring
+func (g Sphere) Cell(ring, col int) Cell {
+	if ring < 0 || ring >= g.Rings() || col < 0 || col >= g.RingSize(ring) {
+		panic(fmt.Sprintf("grid: cell %d, %d is outside the sphere", ring, col))
+	}
+	return g.store.cell(g.shape.starts[ring] + col)
+}
+
+func (g Sphere) CellForIndex(idx int) Cell {
+	checkIndex(idx, g.Size())
+	return g.store.cell(idx)
+}
+
+// ring returns the cells in ring, counting east
+func (g Sphere) ring(ring int) []Cell {
+	cells := make([]Cell, g.RingSize(ring))
+	for c := range cells {
+		cells[c] = g.Cell(ring, c)
+	}
+	return cells
+}
+
+func (g Sphere) CellDir(a, b Cell) Direction {
+	return cellDir(sphereDirections, a, b)
+}
+
+// String prints the globe unrolled, with the north pole along the top and the south pole along the bottom
+// the last cell in each ring wraps around to the first
+func (g Sphere) String() string {
+	var widest int
+	for r := 0; r < g.Rings(); r++ {
+		if g.RingSize(r) > widest {
+			widest = g.RingSize(r)
+		}
+	}
+
+	var builder strings.Builder
+	for r := 0; r < g.Rings(); r++ {
+		ring := g.ring(r)
+		width := widest * 4 / len(ring)
+		for _, cell := range ring { // north walls
+			if cell.HasNeighbor(NORTHWEST) {
+				builder.WriteString("+" + sphereWall(cell, NORTHWEST, width/2-1) + "+" + sphereWall(cell, NORTHEAST, width/2-1))
+			} else {
+				builder.WriteString("+" + sphereWall(cell, NORTH, width-1))
+			}
+		}
+		builder.WriteString("+\n")
+
+		for _, cell := range ring {
+			if cell.open(WEST) {
+				builder.WriteString(" ")
+			} else {
+				builder.WriteString("|")
+			}
+			builder.WriteString(strings.Repeat(" ", width-1))
+		}
+		if ring[len(ring)-1].open(EAST) {
+			builder.WriteString(" \n")
+		} else {
+			builder.WriteString("|\n")
+		}
+	}
+	builder.WriteString(strings.Repeat("+---", widest) + "+\n")
+
+	return builder.String()
+}
+
+func sphereWall(cell Cell, dir Direction, width int) string {
+	if cell.open(dir) {
+		return strings.Repeat(" ", width)
+	}
+	return strings.Repeat("-", width)
+}
+
+// CellRect returns the cell's area on a map of the globe (an equirectangular projection), north pole at the top
+func (g Sphere) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	cellWidth := (size.W() - thickness) / float64(g.RingSize(c.Row()))
+	cellHeight := (size.H() - thickness) / float64(g.Rings())
+	x := float64(c.Col())*cellWidth + thickness              // top left
+	y := float64(g.Rings()-c.Row())*cellHeight + thickness*2 // top left
+
+	return pixel.R(x, y-cellHeight, x+cellWidth, y)
+}
+
+// Draw draws a map of the globe (an equirectangular projection), north pole at the top
+func (g Sphere) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+
+	for r := 0; r < g.Rings(); r++ {
+		ring := g.ring(r)
+		for _, cell := range ring {
+			rect := g.CellRect(cell, size, thickness)
+			topLeft := pixel.V(rect.Min.X, rect.Max.Y)
+
+			if cell.HasNeighbor(NORTHWEST) {
+				middle := pixel.V(rect.Center().X, rect.Max.Y)
+				if !cell.open(NORTHWEST) {
+					target.Push(topLeft, middle)
+					target.Line(thickness)
+				}
+				if !cell.open(NORTHEAST) {
+					target.Push(middle, rect.Max)
+					target.Line(thickness)
+				}
+			} else if !cell.open(NORTH) {
+				target.Push(topLeft, rect.Max)
+				target.Line(thickness)
+			}
+			if !cell.open(WEST) {
+				target.Push(topLeft, rect.Min)
+				target.Line(thickness)
+			}
+			if cell.Col() == len(ring)-1 && !cell.open(EAST) { // the seam at the end of the map
+				target.Push(rect.Max, pixel.V(rect.Max.X, rect.Min.Y))
+				target.Line(thickness)
+			}
+			if cell.Row() == g.Rings()-1 { // south pole
+				target.Push(rect.Min, pixel.V(rect.Max.X, rect.Min.Y))
+				target.Line(thickness)
+			}
+		}
+	}
+
+	drawPortals(target, g, g.store, size, thickness)
+	drawOneWay(target, g, size, thickness)
+
+	target.Draw(window)
+}
@@ -0,0 +1,20 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestSphereSeams(t *testing.T) {
+	for rings := 1; rings <= 12; rings++ {
+		g := NewSphere(rings)
+		checkNeighbors(t, g)
+
+		// every ring wraps around on itself
+		for r := 0; r < g.Rings(); r++ {
+			first, last := g.Cell(r, 0), g.Cell(r, g.RingSize(r)-1)
+			if first.neighbor(WEST) != last.index || last.neighbor(EAST) != first.index {
+				t.Fatalf("%d rings: ring %d doesn't wrap around", rings, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package grid
+
+// shape lays out the cells of a maze, working out where each cell is and what's next to it from its index
+type shape interface {
+	// neighbor returns the index of the cell next to idx in dir, or -1 if there isn't one
+	neighbor(idx int, dir Direction) int
+	position(idx int) (level, row, col int)
+}
+
+// store holds the links of every cell in a maze as packed bits, one for each direction the maze has
+// nothing else is kept per cell, so a plain grid takes half a byte a cell
+type store struct {
+	shape shape
+	slots [DOWN + 1]int8 // the bit each direction is kept in, or -1 if cells can't link that way
+	width int            // bits per cell
+	links []uint64
+	// portals links cells that aren't next to each other. Few cells have them, so they're kept apart from the link bits
+	portals map[int][]int
+	// layers holds the values set in each layer, by name and then by cell index
+	layers map[string]map[int]interface{}
+}
+
+func newStore(s shape, size int, directions []Direction) *store {
+	st := &store{
+		shape: s,
+		width: len(directions),
+	}
+	for d := range st.slots {
+		st.slots[d] = -1
+	}
+	for i, d := range directions {
+		st.slots[d] = int8(i)
+	}
+	st.links = make([]uint64, (size*st.width+63)/64)
+
+	return st
+}
+
+func (s *store) linked(idx int, dir Direction) bool {
+	if s.slots[dir] < 0 {
+		return false
+	}
+	bit := idx*s.width + int(s.slots[dir])
+	return s.links[bit/64]&(1<<(bit%64)) != 0
+}
+
+func (s *store) setLinked(idx int, dir Direction, linked bool) {
+	bit := idx*s.width + int(s.slots[dir])
+	if linked {
+		s.links[bit/64] |= 1 << (bit % 64)
+	} else {
+		s.links[bit/64] &^= 1 << (bit % 64)
+	}
+}
+
+func (s *store) cell(idx int) Cell {
+	return Cell{s, idx}
+}
@@ -0,0 +1,42 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestNeighbors(t *testing.T) {
+	for _, topology := range topologies {
+		for _, size := range [][2]int{{1, 2}, {2, 1}, {2, 2}, {2, 3}, {3, 2}, {4, 5}} {
+			checkNeighbors(t, topology.make(size[0], size[1]))
+		}
+	}
+	checkNeighbors(t, NewUpsilon(5, 6))
+	checkNeighbors(t, NewWeave(5, 6))
+	checkNeighbors(t, New3D(3, 4, 5))
+}
+
+func TestCellBounds(t *testing.T) {
+	g := New(3, 3)
+	g3 := New3D(2, 3, 3)
+	sphere := NewSphere(4)
+	for name, get := range map[string]func(){
+		"east of the grid":    func() { g.Cell(0, 3) },
+		"north of the grid":   func() { g.Cell(-1, 0) },
+		"south of the grid":   func() { g.Cell(3, 0) },
+		"west of the grid":    func() { g.Cell(0, -1) },
+		"past the last cell":  func() { g.CellForIndex(9) },
+		"off a level":         func() { g3.Cell(1, 0, 3) },
+		"past the last level": func() { g3.CellForIndex(18) },
+		"past a ring":         func() { sphere.Cell(0, sphere.RingSize(0)) },
+		"past the last ring":  func() { sphere.Cell(4, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			get()
+		}()
+	}
+}
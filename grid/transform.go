@@ -0,0 +1,162 @@
+package grid
+
+// offsets are the row and column steps to the neighbor in each direction of a flat grid
+var offsets = map[Direction][2]int{
+	NORTH:     {-1, 0},
+	EAST:      {0, 1},
+	SOUTH:     {1, 0},
+	WEST:      {0, -1},
+	NORTHEAST: {-1, 1},
+	SOUTHEAST: {1, 1},
+	SOUTHWEST: {1, -1},
+	NORTHWEST: {-1, -1},
+}
+
+// Rotate returns a copy of the grid turned clockwise by 90 degrees the given number of times
+// like the other transforms, it panics for a level of a 3D grid or a face of a cube, whose links lead off into the rest of their maze,
+// and for a grid with diagonals that it would move an octagon into the top left corner of, which would turn every octagon into a square
+func (g Grid) Rotate(turns int) Grid {
+	switch (turns%4 + 4) % 4 {
+	case 1:
+		return g.transform(g.Cols(), g.Rows(), func(r, c int) (int, int) {
+			return c, g.Rows() - 1 - r
+		})
+	case 2:
+		return g.transform(g.Rows(), g.Cols(), func(r, c int) (int, int) {
+			return g.Rows() - 1 - r, g.Cols() - 1 - c
+		})
+	case 3:
+		return g.transform(g.Cols(), g.Rows(), func(r, c int) (int, int) {
+			return g.Cols() - 1 - c, r
+		})
+	default:
+		return g.transform(g.Rows(), g.Cols(), func(r, c int) (int, int) {
+			return r, c
+		})
+	}
+}
+
+// MirrorHorizontal returns a copy of the grid flipped left to right
+func (g Grid) MirrorHorizontal() Grid {
+	return g.transform(g.Rows(), g.Cols(), func(r, c int) (int, int) {
+		return r, g.Cols() - 1 - c
+	})
+}
+
+// MirrorVertical returns a copy of the grid flipped top to bottom
+func (g Grid) MirrorVertical() Grid {
+	return g.transform(g.Rows(), g.Cols(), func(r, c int) (int, int) {
+		return g.Rows() - 1 - r, c
+	})
+}
+
+// Transpose returns a copy of the grid flipped across the diagonal from its top left corner, so rows become columns
+func (g Grid) Transpose() Grid {
+	return g.transform(g.Cols(), g.Rows(), func(r, c int) (int, int) {
+		return c, r
+	})
+}
+
+// transform returns a rows x cols copy of the grid with the cell at r, c moved to move(r, c)
+// move has to be a rotation or reflection
+func (g Grid) transform(rows, cols int, move func(r, c int) (int, int)) Grid {
+	if !g.standalone() {
+		panic("grid: a level or face of a bigger maze can't be turned or flipped on its own")
+	}
+	if r, c := move(0, 0); g.store.slots[NORTHEAST] >= 0 && !IsOctagon(r, c) {
+		panic("grid: turning or flipping this grid would swap its octagons and squares")
+	}
+	t := g.blank(rows, cols)
+
+	// turn returns where dir points once it's been moved
+	turn := func(dir Direction) Direction {
+		surface := dir
+		if dir.Tunnel() {
+			surface = dir.surface()
+		}
+		r0, c0 := move(1, 1)
+		r, c := move(1+offsets[surface][0], 1+offsets[surface][1])
+		for d, offset := range offsets {
+			if offset == [2]int{r - r0, c - c0} {
+				if dir.Tunnel() {
+					return d.tunnel()
+				}
+				return d
+			}
+		}
+		return dir
+	}
+
+	// edges that wrapped around still do, along whichever axis they end up on
+	flags := g.wrapFlags()
+	if north := turn(NORTH); north == EAST || north == WEST {
+		flags = flags&(wrapEastWest|twistEastWest)<<2 | flags&(wrapNorthSouth|twistNorthSouth)>>2
+	}
+	t.store.shape.(*flat).wrap = flags
+
+	for r := 0; r < g.Rows(); r++ {
+		for c := 0; c < g.Cols(); c++ {
+			from := g.Cell(r, c)
+			tr, tc := move(r, c)
+			to := t.Cell(tr, tc)
+			for _, d := range g.directions {
+				n := from.neighbor(d)
+				if n < 0 || !from.Connected(d) {
+					continue
+				}
+				nr, nc := move(g.store.cell(n).Row(), g.store.cell(n).Col())
+				if td := turn(d); to.neighbor(td) == t.Cell(nr, nc).index {
+					t.store.setLinked(to.index, td, true)
+				}
+			}
+		}
+	}
+
+	for _, pair := range g.store.portalPairs() {
+		ar, ac := move(g.store.cell(pair[0]).Row(), g.store.cell(pair[0]).Col())
+		br, bc := move(g.store.cell(pair[1]).Row(), g.store.cell(pair[1]).Col())
+		t.store.addPortal(t.Cell(ar, ac).index, t.Cell(br, bc).index)
+	}
+	for name, values := range g.store.layers {
+		for idx, v := range values {
+			if idx -= g.offset; idx >= 0 && idx < g.Size() {
+				tr, tc := move(idx/g.cols, idx%g.cols)
+				t.store.setValue(name, t.Cell(tr, tc).index, v)
+			}
+		}
+	}
+
+	return t
+}
+
+func (u Upsilon) Rotate(turns int) Upsilon {
+	return Upsilon{u.Grid.Rotate(turns)}
+}
+
+func (u Upsilon) MirrorHorizontal() Upsilon {
+	return Upsilon{u.Grid.MirrorHorizontal()}
+}
+
+func (u Upsilon) MirrorVertical() Upsilon {
+	return Upsilon{u.Grid.MirrorVertical()}
+}
+
+func (u Upsilon) Transpose() Upsilon {
+	return Upsilon{u.Grid.Transpose()}
+}
+
+func (w Weave) Rotate(turns int) Weave {
+	return Weave{w.Grid.Rotate(turns)}
+}
+
+func (w Weave) MirrorHorizontal() Weave {
+	return Weave{w.Grid.MirrorHorizontal()}
+}
+
+func (w Weave) MirrorVertical() Weave {
+	return Weave{w.Grid.MirrorVertical()}
+}
+
+func (w Weave) Transpose() Weave {
+	return Weave{w.Grid.Transpose()}
+}
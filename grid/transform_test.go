@@ -0,0 +1,71 @@
+package grid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTransformInverses(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, topology := range topologies {
+		for _, size := range [][2]int{{1, 1}, {2, 3}, {4, 4}, {5, 7}} {
+			g := randomPiece(topology.make, size[0], size[1], random)
+
+			for name, transformed := range map[string]Grid{
+				"rotate 4 times":        g.Rotate(1).Rotate(1).Rotate(1).Rotate(1),
+				"rotate 1 and 3":        g.Rotate(1).Rotate(3),
+				"rotate -1 and 1":       g.Rotate(-1).Rotate(1),
+				"rotate 2 twice":        g.Rotate(2).Rotate(2),
+				"mirror horizontally":   g.MirrorHorizontal().MirrorHorizontal(),
+				"mirror vertically":     g.MirrorVertical().MirrorVertical(),
+				"transpose":             g.Transpose().Transpose(),
+				"rotate 0":              g.Rotate(0),
+				"mirror both, rotate 2": g.MirrorHorizontal().MirrorVertical().Rotate(2),
+			} {
+				if !transformed.Equal(g) || !sameLayer(transformed, g, "items") {
+					t.Fatalf("%s %v: %s gave\n%s\nfrom\n%s", topology.name, size, name, transformed, g)
+				}
+			}
+			if a, b := g.Transpose(), g.Rotate(1).MirrorHorizontal(); !a.Equal(b) {
+				t.Fatalf("%s %v: expected transposing to be rotating and mirroring, found\n%s\nand\n%s", topology.name, size, a, b)
+			}
+			if a, b := g.Rotate(2), g.MirrorHorizontal().MirrorVertical(); !a.Equal(b) {
+				t.Fatalf("%s %v: expected rotating twice to be mirroring both ways, found\n%s\nand\n%s", topology.name, size, a, b)
+			}
+		}
+	}
+}
+
+func TestTransformUpsilon(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	u := Upsilon{randomPiece(upsilon, 5, 7, random)}
+	for name, transformed := range map[string]Upsilon{
+		"rotate":              u.Rotate(1).Rotate(3),
+		"mirror horizontally": u.MirrorHorizontal().MirrorHorizontal(),
+		"mirror vertically":   u.MirrorVertical().MirrorVertical(),
+		"transpose":           u.Transpose().Transpose(),
+	} {
+		if !transformed.Equal(u.Grid) {
+			t.Fatalf("%s gave\n%s\nfrom\n%s", name, transformed, u)
+		}
+	}
+	if !u.Rotate(1).Equal(u.Transpose().MirrorHorizontal().Grid) {
+		t.Fatal("expected rotating to be transposing and mirroring")
+	}
+
+	even := NewUpsilon(6, 6)
+	for name, transform := range map[string]func(){
+		"rotate":              func() { even.Rotate(1) },
+		"mirror horizontally": func() { even.MirrorHorizontal() },
+		"mirror vertically":   func() { even.MirrorVertical() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic for turning octagons into squares", name)
+				}
+			}()
+			transform()
+		}()
+	}
+}
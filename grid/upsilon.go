@@ -0,0 +1,131 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+)
+
+// Upsilon is a grid of alternating octagons and squares
+// octagons sit where row+col is even and have diagonal neighbors in addition to the usual four, squares only have the four
+type Upsilon struct {
+	Grid
+}
+
+var upsilonDirections = []Direction{NORTH, EAST, SOUTH, WEST, NORTHEAST, SOUTHEAST, SOUTHWEST, NORTHWEST}
+
+func NewUpsilon(rows, cols int) Upsilon {
+	return Upsilon{newFlat(&flat{rows: rows, cols: cols, diagonals: true}, upsilonDirections)}
+}
+
+// IsOctagon reports whether the cell at row, col is an octagon (as opposed to a square)
+func IsOctagon(row, col int) bool {
+	return (row+col)%2 == 0
+}
+
+// String draws diagonal passages through the corners they cross: \ for northwest-southeast and / for northeast-southwest
+func (u Upsilon) String() string {
+	return u.ascii(func(r, c int) string {
+		if r >= u.Rows() || c >= u.Cols() {
+			return "+"
+		}
+		if u.Cell(r, c).open(NORTHWEST) {
+			return "\\"
+		}
+		if c > 0 && u.Cell(r, c-1).open(NORTHEAST) {
+			return "/"
+		}
+		return "+"
+	}, nil)
+}
+
+// CellRect returns the lattice square around the cell's center, which covers the square cells and most of the octagons
+func (u Upsilon) CellRect(c Cell, size pixel.Rect, thickness float64) pixel.Rect {
+	cellWidth, cellHeight := u.cellSize(size, thickness)
+	center := u.center(c.Row(), c.Col(), cellWidth, cellHeight, size, thickness)
+
+	return pixel.R(center.X-cellWidth/2, center.Y-cellHeight/2, center.X+cellWidth/2, center.Y+cellHeight/2)
+}
+
+// cellSize returns the distance between the centers of orthogonal neighbors
+func (u Upsilon) cellSize(size pixel.Rect, thickness float64) (float64, float64) {
+	// octagons along the edges stick out half an apothem past their cell, so leave room for them
+	cellWidth := (size.W() - thickness*2) / (float64(u.Cols()-1) + math.Sqrt2)
+	cellHeight := (size.H() - thickness*2) / (float64(u.Rows()-1) + math.Sqrt2)
+
+	return cellWidth, cellHeight
+}
+
+func (u Upsilon) center(row, col int, cellWidth, cellHeight float64, size pixel.Rect, thickness float64) pixel.Vec {
+	return pixel.V(
+		size.Min.X+thickness+cellWidth/math.Sqrt2+float64(col)*cellWidth,
+		size.Max.Y-thickness-cellHeight/math.Sqrt2-float64(row)*cellHeight,
+	)
+}
+
+func (u Upsilon) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+	cellWidth, cellHeight := u.cellSize(size, thickness)
+
+	// angle each direction's wall faces, counter clockwise from east
+	angles := map[Direction]float64{
+		EAST:      0,
+		NORTHEAST: 45,
+		NORTH:     90,
+		NORTHWEST: 135,
+		WEST:      180,
+		SOUTHWEST: 225,
+		SOUTH:     270,
+		SOUTHEAST: 315,
+	}
+
+	for r := 0; r < u.Rows(); r++ {
+		for c := 0; c < u.Cols(); c++ {
+			center := u.center(r, c, cellWidth, cellHeight, size, thickness)
+			x, y := center.X, center.Y
+			cell := u.Cell(r, c)
+
+			if IsOctagon(r, c) {
+				// the apothem is half the distance to the diagonal neighbor
+				radiusX := cellWidth / math.Sqrt2 / math.Cos(math.Pi/8)
+				radiusY := cellHeight / math.Sqrt2 / math.Cos(math.Pi/8)
+				for _, d := range u.directions {
+					if cell.open(d) {
+						continue
+					}
+					from := (angles[d] - 22.5) * math.Pi / 180
+					to := (angles[d] + 22.5) * math.Pi / 180
+					target.Push(pixel.V(x+radiusX*math.Cos(from), y+radiusY*math.Sin(from)), pixel.V(x+radiusX*math.Cos(to), y+radiusY*math.Sin(to)))
+					target.Line(thickness)
+				}
+			} else {
+				// squares fill the gap left between four octagons
+				halfW := cellWidth * (1 - 1/math.Sqrt2)
+				halfH := cellHeight * (1 - 1/math.Sqrt2)
+				if !cell.open(NORTH) {
+					target.Push(pixel.V(x-halfW, y+halfH), pixel.V(x+halfW, y+halfH))
+					target.Line(thickness)
+				}
+				if !cell.open(EAST) {
+					target.Push(pixel.V(x+halfW, y+halfH), pixel.V(x+halfW, y-halfH))
+					target.Line(thickness)
+				}
+				if !cell.open(SOUTH) {
+					target.Push(pixel.V(x-halfW, y-halfH), pixel.V(x+halfW, y-halfH))
+					target.Line(thickness)
+				}
+				if !cell.open(WEST) {
+					target.Push(pixel.V(x-halfW, y+halfH), pixel.V(x-halfW, y-halfH))
+					target.Line(thickness)
+				}
+			}
+		}
+	}
+
+	drawPortals(target, u, u.store, size, thickness)
+	drawOneWay(target, u, size, thickness)
+
+	target.Draw(window)
+}
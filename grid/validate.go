@@ -0,0 +1,213 @@
+package grid
+
+// Validation describes how a maze's cells are linked together
+type Validation struct {
+	Components int // groups of cells linked to each other but not to any other group
+	Loops      int // independent loops. Each one is a link that could be removed without splitting the maze
+	// Strong counts groups of cells that can all reach each other. It's more than Components when one-way passages split a component
+	Strong int
+	// Unreachable are the cells outside the largest component
+	Unreachable []Cell
+	// Trapped are the cells in the largest component that one-way passages cut off from the biggest group of cells in it that can all reach each other
+	// they can be reached from it but not get back, or get to it but not be reached from it
+	Trapped []Cell
+	// LoopCells are the cells on a loop, or on a path joining two loops
+	LoopCells []Cell
+}
+
+// Connected reports whether every cell can be reached from every other cell, taking one-way passages only the way they go
+func (v Validation) Connected() bool {
+	return v.Components == 1 && v.Strong == 1
+}
+
+// Perfect reports whether there's exactly one path between any two cells, which makes the maze a spanning tree
+func (v Validation) Perfect() bool {
+	return v.Connected() && v.Loops == 0
+}
+
+// Validate checks how the cells of m are linked together
+// components, loops and dead ends treat one-way passages like any other link, and Strong and Trapped take them only the way they go
+func Validate(m Maze) Validation {
+	var v Validation
+
+	// links[i] is every cell linked to cell i, including through portals. One-way passages count as links both ways
+	// out[i] and in[i] are the cells that can be reached from cell i, and that cell i can be reached from
+	links := make([][]int, m.Size())
+	out := make([][]int, m.Size())
+	in := make([][]int, m.Size())
+	var linkCount int
+	for i := range links {
+		cell := m.CellForIndex(i)
+		for _, d := range m.Directions() {
+			n := cell.neighbor(d)
+			if !cell.Connected(d) {
+				continue
+			}
+			out[i] = append(out[i], n)
+			in[n] = append(in[n], i)
+			// each passage is counted from the cell with the lower index, or from the only side it can be taken from
+			if n > cell.index || cell.OneWay(d) {
+				links[i] = append(links[i], n)
+				links[n] = append(links[n], i)
+				linkCount++
+			}
+		}
+		for _, n := range cell.Portals() {
+			out[i] = append(out[i], n.index)
+			in[n.index] = append(in[n.index], i)
+			if n.index > cell.index {
+				links[i] = append(links[i], n.index)
+				links[n.index] = append(links[n.index], i)
+				linkCount++
+			}
+		}
+	}
+
+	component := make([]int, m.Size())
+	for i := range component {
+		component[i] = -1
+	}
+	var sizes []int
+	for i := range component {
+		if component[i] >= 0 {
+			continue
+		}
+		component[i] = v.Components
+		sizes = append(sizes, 0)
+		queue := []int{i}
+		for len(queue) > 0 {
+			idx := queue[0]
+			queue = queue[1:]
+			sizes[v.Components]++
+			for _, n := range links[idx] {
+				if component[n] < 0 {
+					component[n] = v.Components
+					queue = append(queue, n)
+				}
+			}
+		}
+		v.Components++
+	}
+	v.Loops = linkCount - m.Size() + v.Components
+
+	var largest int
+	for c, size := range sizes {
+		if size > sizes[largest] {
+			largest = c
+		}
+	}
+	for i, c := range component {
+		if c != largest {
+			v.Unreachable = append(v.Unreachable, m.CellForIndex(i))
+		}
+	}
+
+	var strongSizes []int
+	strong := strongComponents(out, in)
+	for i, s := range strong {
+		for s >= len(strongSizes) {
+			strongSizes = append(strongSizes, 0)
+		}
+		if component[i] == largest {
+			strongSizes[s]++
+		}
+	}
+	v.Strong = len(strongSizes)
+	var biggest int
+	for s, size := range strongSizes {
+		if size > strongSizes[biggest] {
+			biggest = s
+		}
+	}
+	for i, s := range strong {
+		if component[i] == largest && s != biggest {
+			v.Trapped = append(v.Trapped, m.CellForIndex(i))
+		}
+	}
+
+	// trim dead ends until only cells on (or between) loops are left
+	degree := make([]int, m.Size())
+	var trim []int
+	for i := range links {
+		degree[i] = len(links[i])
+		if degree[i] <= 1 {
+			trim = append(trim, i)
+		}
+	}
+	for len(trim) > 0 {
+		idx := trim[len(trim)-1]
+		trim = trim[:len(trim)-1]
+		degree[idx] = -1
+		for _, n := range links[idx] {
+			if degree[n] > 0 {
+				degree[n]--
+				if degree[n] == 1 {
+					trim = append(trim, n)
+				}
+			}
+		}
+	}
+	for i, d := range degree {
+		if d > 0 {
+			v.LoopCells = append(v.LoopCells, m.CellForIndex(i))
+		}
+	}
+
+	return v
+}
+
+// strongComponents numbers the groups of cells that can all reach each other, given the cells each one leads out to and in from
+// it's Kosaraju's algorithm: cells are ordered by when a walk along out finishes with them, and then walks along in from the last one finished pick out each group
+func strongComponents(out, in [][]int) []int {
+	type frame struct {
+		idx, next int
+	}
+	visited := make([]bool, len(out))
+	order := make([]int, 0, len(out))
+	for i := range out {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		stack := []frame{{i, 0}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next == len(out[top.idx]) {
+				order = append(order, top.idx)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			n := out[top.idx][top.next]
+			top.next++
+			if !visited[n] {
+				visited[n] = true
+				stack = append(stack, frame{n, 0})
+			}
+		}
+	}
+
+	group := make([]int, len(out))
+	for i := range group {
+		group[i] = -1
+	}
+	var count int
+	for i := len(order) - 1; i >= 0; i-- {
+		if group[order[i]] >= 0 {
+			continue
+		}
+		group[order[i]] = count
+		stack := []int{order[i]}
+		for len(stack) > 0 {
+			idx := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, n := range in[idx] {
+				if group[n] < 0 {
+					group[n] = count
+					stack = append(stack, n)
+				}
+			}
+		}
+		count++
+	}
+	return group
+}
@@ -0,0 +1,23 @@
+package grid
+
+import (
+	"testing"
+)
+
+func TestValidateOneWay(t *testing.T) {
+	g := New(1, 2)
+	g.ConnectOneWay(0, 0, EAST)
+
+	v := Validate(g)
+	if v.Connected() || v.Perfect() {
+		t.Fatalf("a one-way passage can't be walked back, so the maze isn't connected: %+v", v)
+	}
+	if v.Components != 1 || v.Strong != 2 || len(v.Trapped) != 1 {
+		t.Fatalf("expected 1 component split into 2 groups with 1 cell trapped, found %+v", v)
+	}
+
+	g.Connect(0, 0, EAST)
+	if v := Validate(g); !v.Perfect() || len(v.Trapped) != 0 {
+		t.Fatalf("expected a perfect maze once the passage goes both ways, found %+v", v)
+	}
+}
@@ -0,0 +1,97 @@
+package grid
+
+import (
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"image/color"
+	"math"
+)
+
+// Weave is a grid where passages may tunnel under a straight corridor that runs perpendicular to them
+// tunnels link the cells on either side of the corridor through the TUNNEL* directions
+type Weave struct {
+	Grid
+}
+
+var weaveDirections = []Direction{NORTH, EAST, SOUTH, WEST, TUNNELNORTH, TUNNELEAST, TUNNELSOUTH, TUNNELWEST}
+
+func NewWeave(rows, cols int) Weave {
+	return Weave{newFlat(&flat{rows: rows, cols: cols, tunnels: true}, weaveDirections)}
+}
+
+// Cross turns an unlinked cell into a crossing: a corridor running in the over direction with a tunnel passing under it
+// it returns false (and changes nothing) if the cell or any of its neighbors is in the way
+func (w Weave) Cross(row, col int, over Direction) bool {
+	cell := w.Cell(row, col)
+	for _, d := range w.directions {
+		if cell.Connected(d) {
+			return false
+		}
+	}
+	for d := NORTH; d <= WEST; d++ {
+		idx := cell.neighbor(d)
+		if idx < 0 {
+			return false
+		}
+		if n := w.store.cell(idx); n.Connected(d.Reverse()) || n.Connected(d.Reverse().tunnel()) || n.under(d) {
+			return false
+		}
+	}
+
+	cell.connect(over)
+	cell.connect(over.Reverse())
+	under := over.perpendicular()
+	w.store.cell(cell.neighbor(under)).connect(under.Reverse().tunnel())
+
+	return true
+}
+
+func (w Weave) Draw(window pixel.Target, size pixel.Rect, thickness float64) {
+	target := imdraw.New(nil)
+	target.Color = color.White
+
+	cellWidth := (size.W() - thickness) / float64(w.Cols())
+	cellHeight := (size.H() - thickness) / float64(w.Rows())
+	inset := math.Min(cellWidth, cellHeight) * 0.15
+
+	for r := 0; r < w.Rows(); r++ {
+		y := float64(w.Rows()-r)*cellHeight + thickness*2 // top left
+		for c := 0; c < w.Cols(); c++ {
+			x := float64(c)*cellWidth + thickness // top left
+			cell := w.Cell(r, c)
+
+			// the cell's floor, and the cell edge on each side of it
+			inner := [WEST + 1][2]pixel.Vec{
+				NORTH: {pixel.V(x+inset, y-inset), pixel.V(x+cellWidth-inset, y-inset)},
+				EAST:  {pixel.V(x+cellWidth-inset, y-inset), pixel.V(x+cellWidth-inset, y-cellHeight+inset)},
+				SOUTH: {pixel.V(x+inset, y-cellHeight+inset), pixel.V(x+cellWidth-inset, y-cellHeight+inset)},
+				WEST:  {pixel.V(x+inset, y-inset), pixel.V(x+inset, y-cellHeight+inset)},
+			}
+			outer := [WEST + 1]pixel.Vec{
+				NORTH: pixel.V(0, inset),
+				EAST:  pixel.V(inset, 0),
+				SOUTH: pixel.V(0, -inset),
+				WEST:  pixel.V(-inset, 0),
+			}
+
+			for d := NORTH; d <= WEST; d++ {
+				open := cell.open(d) || cell.open(d.tunnel())
+				if open || cell.under(d) { // passage walls out to the edge of the cell
+					for _, v := range inner[d] {
+						target.Push(v, v.Add(outer[d]))
+						target.Line(thickness)
+					}
+				}
+				if !open {
+					target.Push(inner[d][0], inner[d][1])
+					target.Line(thickness)
+				}
+			}
+		}
+	}
+
+	drawPortals(target, w, w.store, size, thickness)
+	drawOneWay(target, w, size, thickness)
+
+	target.Draw(window)
+}
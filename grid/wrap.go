@@ -0,0 +1,63 @@
+package grid
+
+// NewCylinder returns a grid whose east edge wraps around to its west edge
+func NewCylinder(rows, cols int) Grid {
+	g := New(rows, cols)
+	g.wrapEastWest(false)
+
+	return g
+}
+
+// NewTorus returns a grid whose east edge wraps around to its west edge and whose south edge wraps around to its north edge
+// mazes on a torus tile seamlessly in both directions
+func NewTorus(rows, cols int) Grid {
+	g := New(rows, cols)
+	g.wrapEastWest(false)
+	g.wrapNorthSouth(false)
+
+	return g
+}
+
+// NewMobius returns a grid whose east edge wraps around to its west edge upside down, so the top row continues into the bottom row
+func NewMobius(rows, cols int) Grid {
+	g := New(rows, cols)
+	g.wrapEastWest(true)
+
+	return g
+}
+
+// NewKlein returns a grid whose east edge wraps around to its west edge and whose south edge wraps around to its north edge back to front,
+// so the first column continues into the last column
+func NewKlein(rows, cols int) Grid {
+	g := New(rows, cols)
+	g.wrapEastWest(false)
+	g.wrapNorthSouth(true)
+
+	return g
+}
+
+// how a flat grid's edges wrap around to each other
+const (
+	wrapEastWest = 1 << iota
+	twistEastWest
+	wrapNorthSouth
+	twistNorthSouth
+)
+
+// wrapEastWest links the east edge to the west edge, flipping it over if twist is set
+func (g Grid) wrapEastWest(twist bool) {
+	f := g.store.shape.(*flat)
+	f.wrap |= wrapEastWest
+	if twist {
+		f.wrap |= twistEastWest
+	}
+}
+
+// wrapNorthSouth links the south edge to the north edge, flipping it over if twist is set
+func (g Grid) wrapNorthSouth(twist bool) {
+	f := g.store.shape.(*flat)
+	f.wrap |= wrapNorthSouth
+	if twist {
+		f.wrap |= twistNorthSouth
+	}
+}
@@ -27,6 +27,7 @@ type menuSettings struct {
 	longestPath  bool
 	floodFill    bool
 	showDijkstra bool
+	wallFollow   bool
 
 	start *grid.Cell
 	end   *grid.Cell
@@ -128,6 +129,13 @@ func run() {
 			regrid = true
 			repaint = true
 		}
+		if win.JustPressed(pixelgl.Key8) {
+			algorithm = algorithms.Kruskals
+			settings.algorithm = 8
+
+			regrid = true
+			repaint = true
+		}
 
 		if win.JustPressed(pixelgl.KeyN) {
 			regrid = true
@@ -157,6 +165,10 @@ func run() {
 			settings.longestPath = !settings.longestPath
 			repaint = true
 		}
+		if win.JustPressed(pixelgl.KeyW) {
+			settings.wallFollow = !settings.wallFollow
+			repaint = true
+		}
 
 		if regrid {
 			g = grid.New(g.Rows(), g.Cols())
@@ -210,6 +222,9 @@ func run() {
 			if settings.longestPath {
 				dj.DrawLongestPath(win, graphRect, thickness)
 			}
+			if settings.wallFollow && settings.start != nil && settings.end != nil {
+				algorithms.LeftHand(g, *settings.start, *settings.end).Draw(g, win, graphRect, thickness)
+			}
 
 			DrawStartEnd(g, win, graphRect, settings)
 			g.Draw(win, graphRect, thickness)
@@ -330,6 +345,12 @@ func DrawMenu(target pixel.Target, bounds pixel.Rect, settings menuSettings) {
 		labelWriter.Color = color.White
 	}
 	labelWriter.WriteString("7 - Recursive Backtracker\n")
+	if settings.algorithm == 8 {
+		labelWriter.Color = green
+	} else {
+		labelWriter.Color = color.White
+	}
+	labelWriter.WriteString("8 - Kruskal's\n")
 	labelWriter.Color = color.White
 	labelWriter.WriteRune('\n')
 	labelWriter.WriteString("commands:\n")
@@ -347,6 +368,11 @@ func DrawMenu(target pixel.Target, bounds pixel.Rect, settings menuSettings) {
 	}
 	labelWriter.WriteString("l - show longest path from start\n")
 	labelWriter.Color = color.White
+	if settings.wallFollow {
+		labelWriter.Color = green
+	}
+	labelWriter.WriteString("w - follow the left wall from start to end\n")
+	labelWriter.Color = color.White
 	if settings.floodFill {
 		labelWriter.Color = green
 	}
@@ -369,7 +395,7 @@ func main() {
 		_ = set.Parse(os.Args[1:])
 
 		if stats {
-			algos := []func(g grid.Grid){
+			algos := []func(g grid.Maze){
 				algorithms.BinarySearch,
 				algorithms.Sidewinder,
 				algorithms.AldousBroder,
@@ -377,6 +403,7 @@ func main() {
 				algorithms.AldousBroderWilsons,
 				algorithms.HuntAndKill,
 				algorithms.RecursiveBacktracker,
+				algorithms.Kruskals,
 			}
 			names := make([]string, len(algos))
 			var longestName int